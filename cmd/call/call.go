@@ -0,0 +1,184 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package call
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/aurae-runtime/ae/pkg/client"
+	"github.com/aurae-runtime/ae/pkg/config"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	aeCMD "github.com/aurae-runtime/ae/cmd"
+	pkgcall "github.com/aurae-runtime/ae/pkg/call"
+)
+
+type option struct {
+	aeCMD.Option
+	ctx      context.Context
+	auth     *config.Auth
+	target   string
+	method   string
+	data     string
+	port     uint16
+	protocol string
+	verbose  bool
+	writer   io.Writer
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) != 2 {
+		return errors.New("expected target and method to be passed to this command")
+	}
+	o.target = args[0]
+	o.method = args[1]
+	return nil
+}
+
+func (o *option) Validate() error {
+	if len(o.target) == 0 {
+		return errors.New("target must be passed to this command")
+	}
+	if ip := net.ParseIP(o.target); ip == nil && !strings.HasPrefix(o.target, "/") {
+		return fmt.Errorf("target %q is neither an IP address nor an absolute socket path", o.target)
+	}
+	if _, _, err := pkgcall.SplitMethod(o.method); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *option) Execute(ctx context.Context) error {
+	o.ctx = ctx
+
+	socket := o.target
+	o.protocol = "unix"
+	if ip := net.ParseIP(o.target); ip != nil {
+		o.protocol = "tcp4"
+		if ip.To4() == nil {
+			o.protocol = "tcp6"
+		}
+		socket = net.JoinHostPort(o.target, fmt.Sprintf("%d", o.port))
+	}
+
+	if o.verbose {
+		log.Printf("connecting to %s using protocol %s\n", socket, o.protocol)
+	}
+
+	c, err := client.New(o.ctx, config.WithAuth(*o.auth), config.WithSystem(config.System{Protocol: o.protocol, Socket: socket}))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	cl, err := c.Call()
+	if err != nil {
+		return err
+	}
+
+	md, err := cl.Resolve(o.ctx, o.method)
+	if err != nil {
+		return err
+	}
+
+	body, err := o.body()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	// Streaming responses are printed as newline delimited JSON so they can
+	// be piped into tools processing one message per line.
+	marshal := protojson.MarshalOptions{Multiline: true, Indent: "    "}
+	if md.IsStreamingServer() {
+		marshal = protojson.MarshalOptions{}
+	}
+
+	return cl.Invoke(o.ctx, md, body, func(rsp proto.Message) error {
+		b, err := marshal.Marshal(rsp)
+		if err != nil {
+			return err
+		}
+		_, err = o.writer.Write(append(b, '\n'))
+		return err
+	})
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+// body returns the request body. A data argument starting with '@' names a
+// file to read the body from, where '@-' reads from stdin.
+func (o *option) body() (io.ReadCloser, error) {
+	switch {
+	case o.data == "@-":
+		return io.NopCloser(os.Stdin), nil
+	case strings.HasPrefix(o.data, "@"):
+		f, err := os.Open(strings.TrimPrefix(o.data, "@"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open request body: %w", err)
+		}
+		return f, nil
+	default:
+		return io.NopCloser(strings.NewReader(o.data)), nil
+	}
+}
+
+func NewCMD(ctx context.Context) *cobra.Command {
+	o := &option{
+		auth: &config.Auth{},
+	}
+	cmd := &cobra.Command{
+		Use:   "call <ip|socket> <package.Service/Method>",
+		Short: "Calls an arbitrary gRPC method on the given Aurae node using a JSON request body.",
+		Example: `ae call 10.1.1.4 aurae.cells.v0.CellService/List
+ae call 10.1.1.4 aurae.cells.v0.CellService/Free -d '{"cell_name": "web"}'
+ae call /var/run/aurae/aurae.sock aurae.observe.v0.ObserveService/GetAuraeDaemonLogStream`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return aeCMD.Run(ctx, o, cmd, args)
+		},
+	}
+	o.auth.AddFlags(cmd)
+	cmd.Flags().StringVarP(&o.data, "data", "d", o.data, "JSON request body. Use @<file> to read it from a file or @- to read it from stdin.")
+	cmd.Flags().Uint16Var(&o.port, "port", o.port, "The port to use when connecting")
+	cmd.Flags().BoolVar(&o.verbose, "verbose", o.verbose, "Lots of output")
+	return cmd
+}
@@ -0,0 +1,94 @@
+package call
+
+import (
+	"testing"
+)
+
+func TestComplete(t *testing.T) {
+	ts := []struct {
+		args       []string
+		wanttarget string
+		wantmethod string
+		wanterr    bool
+	}{
+		{
+			[]string{"10.0.0.1"},
+			"", "", true,
+		},
+		{
+			[]string{"10.0.0.1", "aurae.cells.v0.CellService/List"},
+			"10.0.0.1", "aurae.cells.v0.CellService/List", false,
+		},
+		{
+			[]string{"10.0.0.1", "aurae.cells.v0.CellService/List", "foo"},
+			"", "", true,
+		},
+	}
+
+	for _, tt := range ts {
+		o := &option{}
+		goterr := o.Complete(tt.args)
+		if tt.wanterr && goterr == nil {
+			t.Fatal("want error, got no error")
+		}
+		if !tt.wanterr && goterr != nil {
+			t.Fatal("want no error, got error")
+		}
+		if tt.wanttarget != o.target {
+			t.Fatalf("want target %q, got target %q", tt.wanttarget, o.target)
+		}
+		if tt.wantmethod != o.method {
+			t.Fatalf("want method %q, got method %q", tt.wantmethod, o.method)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	ts := []struct {
+		name    string
+		target  string
+		method  string
+		wanterr bool
+	}{
+		{
+			name:    "no target",
+			method:  "aurae.cells.v0.CellService/List",
+			wanterr: true,
+		},
+		{
+			name:    "invalid target",
+			target:  "invalid ip",
+			method:  "aurae.cells.v0.CellService/List",
+			wanterr: true,
+		},
+		{
+			name:    "invalid method",
+			target:  "10.0.0.1",
+			method:  "List",
+			wanterr: true,
+		},
+		{
+			name:    "valid ip and method",
+			target:  "10.0.0.1",
+			method:  "aurae.cells.v0.CellService/List",
+			wanterr: false,
+		},
+		{
+			name:    "valid socket and method",
+			target:  "/var/run/aurae/aurae.sock",
+			method:  "aurae.cells.v0.CellService/List",
+			wanterr: false,
+		},
+	}
+
+	for _, tt := range ts {
+		o := &option{target: tt.target, method: tt.method}
+		goterr := o.Validate()
+		if tt.wanterr && goterr == nil {
+			t.Fatalf("[%s] want error, got no error", tt.name)
+		}
+		if !tt.wanterr && goterr != nil {
+			t.Fatalf("[%s] want no error, got error: %s", tt.name, goterr)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package containers
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	aeCMD "github.com/aurae-runtime/ae/cmd"
+	"github.com/aurae-runtime/ae/pkg/cli"
+	"github.com/aurae-runtime/ae/pkg/cli/printer"
+	"github.com/aurae-runtime/ae/pkg/client"
+	"github.com/aurae-runtime/ae/pkg/config"
+	"github.com/aurae-runtime/ae/pkg/cri"
+	"github.com/spf13/cobra"
+
+	criv1 "github.com/aurae-runtime/ae/pkg/api/cri/v1"
+)
+
+type option struct {
+	aeCMD.Option
+	auth         *config.Auth
+	outputFormat *cli.OutputFormat
+	id           string
+	pod          string
+	state        string
+	labels       []string
+	filter       *criv1.ContainerFilter
+	writer       io.Writer
+}
+
+func (o *option) Complete(_ []string) error {
+	labels, err := cri.ParseLabels(o.labels)
+	if err != nil {
+		return err
+	}
+	o.filter = &criv1.ContainerFilter{Id: o.id, PodSandboxId: o.pod, LabelSelector: labels}
+	if len(o.state) != 0 {
+		state, err := cri.ParseContainerState(o.state)
+		if err != nil {
+			return err
+		}
+		o.filter.State = &criv1.ContainerStateValue{State: state}
+	}
+	return nil
+}
+
+func (o *option) Validate() error {
+	return o.outputFormat.Validate()
+}
+
+func (o *option) Execute(ctx context.Context) error {
+	c, err := client.New(ctx, config.WithAuth(*o.auth))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	rt, err := c.CRI()
+	if err != nil {
+		return err
+	}
+
+	rsp, err := rt.ListContainers(ctx, &criv1.ListContainersRequest{Filter: o.filter})
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+	return o.outputFormat.ToPrinter().Print(o.writer, cri.NewContainers(rsp.Containers))
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func NewCMD(ctx context.Context) *cobra.Command {
+	o := &option{
+		auth: &config.Auth{},
+		outputFormat: cli.NewOutputFormat().
+			WithDefaultFormat(printer.NewText().Format()).
+			WithPrinter(printer.NewText()).
+			WithPrinter(printer.NewJSON()).
+			WithPrinter(printer.NewYAML()),
+	}
+	cmd := &cobra.Command{
+		Use:   "containers",
+		Short: "List the containers of the CRI runtime service.",
+		Example: `ae cri containers
+ae cri containers --pod <pod-id> --state running -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return aeCMD.Run(ctx, o, cmd, args)
+		},
+	}
+	o.auth.AddFlags(cmd)
+	o.outputFormat.AddFlags(cmd)
+	cmd.Flags().StringVar(&o.id, "id", o.id, "Only list the container with the given id")
+	cmd.Flags().StringVar(&o.pod, "pod", o.pod, "Only list containers of the pod with the given id")
+	cmd.Flags().StringVar(&o.state, "state", o.state, "Only list containers in the given state. One of: (created, running, exited, unknown).")
+	cmd.Flags().StringArrayVarP(&o.labels, "label", "l", o.labels, "Only list containers with the given label in the form key=value. Can be repeated.")
+	return cmd
+}
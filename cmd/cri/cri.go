@@ -0,0 +1,91 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package cri
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	aeCMD "github.com/aurae-runtime/ae/cmd"
+	"github.com/aurae-runtime/ae/cmd/cri/containers"
+	"github.com/aurae-runtime/ae/cmd/cri/inspect"
+	"github.com/aurae-runtime/ae/cmd/cri/pods"
+	"github.com/aurae-runtime/ae/cmd/cri/runpod"
+	"github.com/aurae-runtime/ae/cmd/cri/stoppod"
+	"github.com/spf13/cobra"
+)
+
+type option struct {
+	aeCMD.Option
+	writer io.Writer
+}
+
+func (o *option) Complete(_ []string) error {
+	return nil
+}
+
+func (o *option) Validate() error {
+	return nil
+}
+
+func (o *option) Execute(_ context.Context) error {
+	fmt.Fprintln(o.writer, "cri called")
+	return nil
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func NewCMD(ctx context.Context) *cobra.Command {
+	o := &option{}
+	cmd := &cobra.Command{
+		Use:   "cri",
+		Short: "CLI for the Kubernetes CRI runtime service of aurae.",
+		Long: `CLI for the Kubernetes CRI runtime service of aurae.
+
+The requests are sent over the same mTLS connection as every other ae
+command, which allows debugging kubelet driven workloads on aurae nodes
+without crictl and its socket setup.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return aeCMD.Run(ctx, o, cmd, args)
+		},
+	}
+
+	cmd.AddCommand(containers.NewCMD(ctx))
+	cmd.AddCommand(inspect.NewCMD(ctx))
+	cmd.AddCommand(pods.NewCMD(ctx))
+	cmd.AddCommand(runpod.NewCMD(ctx))
+	cmd.AddCommand(stoppod.NewCMD(ctx))
+
+	return cmd
+}
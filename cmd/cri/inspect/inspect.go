@@ -0,0 +1,145 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package inspect
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	aeCMD "github.com/aurae-runtime/ae/cmd"
+	"github.com/aurae-runtime/ae/pkg/cli"
+	"github.com/aurae-runtime/ae/pkg/cli/printer"
+	"github.com/aurae-runtime/ae/pkg/client"
+	"github.com/aurae-runtime/ae/pkg/config"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	criv1 "github.com/aurae-runtime/ae/pkg/api/cri/v1"
+)
+
+type option struct {
+	aeCMD.Option
+	auth         *config.Auth
+	outputFormat *cli.OutputFormat
+	id           string
+	writer       io.Writer
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) != 1 {
+		return errors.New("expected pod or container id to be passed to this command")
+	}
+	o.id = args[0]
+	return nil
+}
+
+func (o *option) Validate() error {
+	if len(o.id) == 0 {
+		return errors.New("pod or container id must not be empty")
+	}
+	return o.outputFormat.Validate()
+}
+
+func (o *option) Execute(ctx context.Context) error {
+	c, err := client.New(ctx, config.WithAuth(*o.auth))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	rt, err := c.CRI()
+	if err != nil {
+		return err
+	}
+
+	// The id is looked up as a pod first and as a container if no pod
+	// has it.
+	var rsp proto.Message
+	rsp, err = rt.PodSandboxStatus(ctx, &criv1.PodSandboxStatusRequest{PodSandboxId: o.id, Verbose: true})
+	if status.Code(err) == codes.NotFound {
+		rsp, err = rt.ContainerStatus(ctx, &criv1.ContainerStatusRequest{ContainerId: o.id, Verbose: true})
+		if status.Code(err) == codes.NotFound {
+			return fmt.Errorf("no pod or container with id %q", o.id)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to inspect %q: %w", o.id, err)
+	}
+
+	out, err := toMap(rsp)
+	if err != nil {
+		return err
+	}
+	return o.outputFormat.ToPrinter().Print(o.writer, out)
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+// toMap converts the status to a generic map using the field names of the
+// protobuf JSON mapping, so the printers render it the way crictl does.
+func toMap(m proto.Message) (map[string]interface{}, error) {
+	b, err := protojson.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode status: %w", err)
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, fmt.Errorf("failed to encode status: %w", err)
+	}
+	return out, nil
+}
+
+func NewCMD(ctx context.Context) *cobra.Command {
+	o := &option{
+		auth: &config.Auth{},
+		outputFormat: cli.NewOutputFormat().
+			WithDefaultFormat(printer.NewJSON().Format()).
+			WithPrinter(printer.NewJSON()).
+			WithPrinter(printer.NewYAML()),
+	}
+	cmd := &cobra.Command{
+		Use:   "inspect <pod-id|container-id>",
+		Short: "Print the verbose CRI status of a pod sandbox or container.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return aeCMD.Run(ctx, o, cmd, args)
+		},
+	}
+	o.auth.AddFlags(cmd)
+	o.outputFormat.AddFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,51 @@
+package pods
+
+import (
+	"reflect"
+	"testing"
+
+	criv1 "github.com/aurae-runtime/ae/pkg/api/cri/v1"
+)
+
+func TestComplete(t *testing.T) {
+	ts := []struct {
+		name    string
+		o       option
+		want    *criv1.PodSandboxFilter
+		wanterr bool
+	}{
+		{
+			name: "no filter",
+			want: &criv1.PodSandboxFilter{},
+		},
+		{
+			name: "all filters",
+			o:    option{id: "abc", state: "ready", labels: []string{"app=web"}},
+			want: &criv1.PodSandboxFilter{
+				Id:            "abc",
+				State:         &criv1.PodSandboxStateValue{State: criv1.PodSandboxState_SANDBOX_READY},
+				LabelSelector: map[string]string{"app": "web"},
+			},
+		},
+		{
+			name:    "invalid state",
+			o:       option{state: "running"},
+			wanterr: true,
+		},
+		{
+			name:    "invalid label",
+			o:       option{labels: []string{"app"}},
+			wanterr: true,
+		},
+	}
+
+	for _, tt := range ts {
+		err := tt.o.Complete(nil)
+		if tt.wanterr != (err != nil) {
+			t.Fatalf("[%s] want error %v, got %v", tt.name, tt.wanterr, err)
+		}
+		if !tt.wanterr && !reflect.DeepEqual(tt.o.filter, tt.want) {
+			t.Fatalf("[%s] want filter %+v, got %+v", tt.name, tt.want, tt.o.filter)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package runpod
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	aeCMD "github.com/aurae-runtime/ae/cmd"
+	"github.com/aurae-runtime/ae/pkg/cli"
+	"github.com/aurae-runtime/ae/pkg/cli/printer"
+	"github.com/aurae-runtime/ae/pkg/client"
+	"github.com/aurae-runtime/ae/pkg/config"
+	"github.com/aurae-runtime/ae/pkg/cri"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	criv1 "github.com/aurae-runtime/ae/pkg/api/cri/v1"
+)
+
+type outputPod struct {
+	ID string `json:"id"`
+}
+
+func (p outputPod) String() string {
+	return p.ID
+}
+
+type option struct {
+	aeCMD.Option
+	auth           *config.Auth
+	outputFormat   *cli.OutputFormat
+	name           string
+	namespace      string
+	uid            string
+	attempt        uint32
+	hostname       string
+	logDir         string
+	labels         []string
+	annotations    []string
+	configFile     string
+	runtimeHandler string
+	config         *criv1.PodSandboxConfig
+	writer         io.Writer
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) > 1 {
+		return errors.New("expected at most a pod name to be passed to this command")
+	}
+	if len(args) == 1 {
+		o.name = args[0]
+	}
+	if len(o.name) != 0 && len(o.configFile) != 0 {
+		return errors.New("either a pod name or --config must be passed to this command, not both")
+	}
+
+	o.config = &criv1.PodSandboxConfig{}
+	if len(o.configFile) != 0 {
+		b, err := os.ReadFile(o.configFile)
+		if err != nil {
+			return fmt.Errorf("failed to read pod config: %w", err)
+		}
+		if err := protojson.Unmarshal(b, o.config); err != nil {
+			return fmt.Errorf("failed to parse pod config %s: %w", o.configFile, err)
+		}
+	} else {
+		if len(o.uid) == 0 {
+			uid, err := newUID()
+			if err != nil {
+				return err
+			}
+			o.uid = uid
+		}
+		o.config.Metadata = &criv1.PodSandboxMetadata{
+			Name:      o.name,
+			Namespace: o.namespace,
+			Uid:       o.uid,
+			Attempt:   o.attempt,
+		}
+	}
+
+	if len(o.hostname) != 0 {
+		o.config.Hostname = o.hostname
+	}
+	if len(o.logDir) != 0 {
+		o.config.LogDirectory = o.logDir
+	}
+	if err := merge(&o.config.Labels, o.labels); err != nil {
+		return err
+	}
+	return merge(&o.config.Annotations, o.annotations)
+}
+
+func (o *option) Validate() error {
+	if err := o.outputFormat.Validate(); err != nil {
+		return err
+	}
+	md := o.config.GetMetadata()
+	if len(md.GetName()) == 0 {
+		return errors.New("pod name must be passed to this command")
+	}
+	if len(md.GetNamespace()) == 0 || len(md.GetUid()) == 0 {
+		return fmt.Errorf("pod %q requires a namespace and a uid", md.GetName())
+	}
+	return nil
+}
+
+func (o *option) Execute(ctx context.Context) error {
+	c, err := client.New(ctx, config.WithAuth(*o.auth))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	rt, err := c.CRI()
+	if err != nil {
+		return err
+	}
+
+	rsp, err := rt.RunPodSandbox(ctx, &criv1.RunPodSandboxRequest{
+		Config:         o.config,
+		RuntimeHandler: o.runtimeHandler,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to run pod %q: %w", o.config.GetMetadata().GetName(), err)
+	}
+	return o.outputFormat.ToPrinter().Print(o.writer, outputPod{ID: rsp.PodSandboxId})
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+// merge adds the key=value pairs to labels, overriding existing keys.
+func merge(labels *map[string]string, pairs []string) error {
+	parsed, err := cri.ParseLabels(pairs)
+	if err != nil {
+		return err
+	}
+	for k, v := range parsed {
+		if *labels == nil {
+			*labels = make(map[string]string, len(parsed))
+		}
+		(*labels)[k] = v
+	}
+	return nil
+}
+
+// newUID returns a random version 4 UUID, as kubelet uses for pod UIDs.
+func newUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate pod uid: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+func NewCMD(ctx context.Context) *cobra.Command {
+	o := &option{
+		auth:      &config.Auth{},
+		namespace: "default",
+		outputFormat: cli.NewOutputFormat().
+			WithDefaultFormat(printer.NewText().Format()).
+			WithPrinter(printer.NewText()).
+			WithPrinter(printer.NewJSON()),
+	}
+	cmd := &cobra.Command{
+		Use:   "run-pod [name]",
+		Short: "Run a pod sandbox through the CRI runtime service and print its id.",
+		Long: `Run a pod sandbox through the CRI runtime service and print its id.
+
+The pod is either described by flags or read from a JSON encoded
+PodSandboxConfig passed with --config, as accepted by crictl runp. Labels,
+annotations, hostname and log directory flags are applied on top of the
+config file.`,
+		Example: `ae cri run-pod web --namespace default --label app=web
+ae cri run-pod --config pod.json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return aeCMD.Run(ctx, o, cmd, args)
+		},
+	}
+	o.auth.AddFlags(cmd)
+	o.outputFormat.AddFlags(cmd)
+	cmd.Flags().StringVarP(&o.namespace, "namespace", "n", o.namespace, "Namespace of the pod")
+	cmd.Flags().StringVar(&o.uid, "uid", o.uid, "UID of the pod, a random UID is used if empty")
+	cmd.Flags().Uint32Var(&o.attempt, "attempt", o.attempt, "Attempt number of the pod")
+	cmd.Flags().StringVar(&o.hostname, "hostname", o.hostname, "Hostname of the pod")
+	cmd.Flags().StringVar(&o.logDir, "log-dir", o.logDir, "Directory the container logs of the pod are written to")
+	cmd.Flags().StringArrayVarP(&o.labels, "label", "l", o.labels, "Label of the pod in the form key=value. Can be repeated.")
+	cmd.Flags().StringArrayVar(&o.annotations, "annotation", o.annotations, "Annotation of the pod in the form key=value. Can be repeated.")
+	cmd.Flags().StringVar(&o.configFile, "config", o.configFile, "JSON file containing the PodSandboxConfig of the pod")
+	cmd.Flags().StringVar(&o.runtimeHandler, "runtime-handler", o.runtimeHandler, "Runtime handler to run the pod with")
+	return cmd
+}
@@ -0,0 +1,51 @@
+package runpod
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/aurae-runtime/ae/pkg/cli"
+	"github.com/aurae-runtime/ae/pkg/cli/printer"
+)
+
+func newOption() *option {
+	return &option{
+		namespace:    "default",
+		outputFormat: cli.NewOutputFormat().WithDefaultFormat("text").WithPrinter(printer.NewText()),
+	}
+}
+
+func TestComplete(t *testing.T) {
+	o := newOption()
+	o.labels = []string{"app=web"}
+	if err := o.Complete([]string{"web"}); err != nil {
+		t.Fatalf("want no error, got %s", err)
+	}
+	if err := o.Validate(); err != nil {
+		t.Fatalf("want no error, got %s", err)
+	}
+	md := o.config.Metadata
+	if md.Name != "web" || md.Namespace != "default" {
+		t.Fatalf("unexpected metadata %+v", md)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(md.Uid) {
+		t.Fatalf("want random uuid as uid, got %q", md.Uid)
+	}
+	if o.config.Labels["app"] != "web" {
+		t.Fatalf("want label app=web, got %v", o.config.Labels)
+	}
+
+	o = newOption()
+	o.configFile = "pod.json"
+	if err := o.Complete([]string{"web"}); err == nil {
+		t.Fatal("want error for name and config, got no error")
+	}
+
+	o = newOption()
+	if err := o.Complete(nil); err != nil {
+		t.Fatalf("want no error, got %s", err)
+	}
+	if err := o.Validate(); err == nil {
+		t.Fatal("want error for missing name, got no error")
+	}
+}
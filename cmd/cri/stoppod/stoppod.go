@@ -0,0 +1,116 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package stoppod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	aeCMD "github.com/aurae-runtime/ae/cmd"
+	"github.com/aurae-runtime/ae/pkg/client"
+	"github.com/aurae-runtime/ae/pkg/config"
+	"github.com/spf13/cobra"
+
+	criv1 "github.com/aurae-runtime/ae/pkg/api/cri/v1"
+)
+
+type option struct {
+	aeCMD.Option
+	auth   *config.Auth
+	ids    []string
+	writer io.Writer
+}
+
+func (o *option) Complete(args []string) error {
+	o.ids = args
+	return nil
+}
+
+func (o *option) Validate() error {
+	if len(o.ids) == 0 {
+		return errors.New("expected at least one pod id to be passed to this command")
+	}
+	for _, id := range o.ids {
+		if len(id) == 0 {
+			return errors.New("pod id must not be empty")
+		}
+	}
+	return nil
+}
+
+func (o *option) Execute(ctx context.Context) error {
+	c, err := client.New(ctx, config.WithAuth(*o.auth))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	rt, err := c.CRI()
+	if err != nil {
+		return err
+	}
+
+	// Stopping continues past failures, so a single broken pod does not
+	// keep the remaining ones running.
+	var errs []error
+	for _, id := range o.ids {
+		if _, err := rt.StopPodSandbox(ctx, &criv1.StopPodSandboxRequest{PodSandboxId: id}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop pod %q: %w", id, err))
+			continue
+		}
+		fmt.Fprintln(o.writer, id)
+	}
+	return errors.Join(errs...)
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func NewCMD(ctx context.Context) *cobra.Command {
+	o := &option{
+		auth: &config.Auth{},
+	}
+	cmd := &cobra.Command{
+		Use:   "stop-pod <pod-id>...",
+		Short: "Stop pod sandboxes through the CRI runtime service.",
+		Long: `Stop pod sandboxes through the CRI runtime service.
+
+The containers of every pod are stopped and the ids of the stopped pods are
+printed.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return aeCMD.Run(ctx, o, cmd, args)
+		},
+	}
+	o.auth.AddFlags(cmd)
+	return cmd
+}
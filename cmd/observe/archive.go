@@ -0,0 +1,403 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package observe
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aurae-runtime/ae/pkg/cli/printer"
+)
+
+// sink receives every observed item in addition to the printed output.
+type sink interface {
+	Write(item outputItem) error
+	Close() error
+}
+
+const manifestFile = "manifest.json"
+
+// unnamedChannel is archived for items that carry no channel name, so
+// their file is not named just .log.
+const unnamedChannel = "unnamed"
+
+// manifestEntry describes a single archived file of a node.
+type manifestEntry struct {
+	File    string    `json:"file"`
+	Channel string    `json:"channel"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Items   int       `json:"items"`
+	Bytes   int64     `json:"bytes"`
+	// Recovered marks a file left behind by a previous run that ended
+	// without rotating it. Its time range is the time it was last written
+	// to and its items are not counted.
+	Recovered bool `json:"recovered,omitempty"`
+	// Active marks the file currently written to. Its time range starts
+	// when it was opened and its items are only counted once it is rotated.
+	Active bool `json:"active,omitempty"`
+}
+
+type manifest struct {
+	Node  string          `json:"node"`
+	Files []manifestEntry `json:"files"`
+}
+
+// archive writes items into one file per node and channel below dir, e.g.
+// <dir>/10.1.1.4/auraed.log. Files are rotated once they exceed maxSize
+// bytes or have been open for longer than maxAge, and optionally compressed
+// with gzip. Every rotated file is listed with the time range of its items
+// in the manifest.json of its node directory, next to the files currently
+// written to. Closing the archive rotates all open files. An archive is safe
+// for concurrent use.
+type archive struct {
+	mu       sync.Mutex
+	dir      string
+	maxSize  int64
+	maxAge   time.Duration
+	compress bool
+	printer  printer.Interface
+	now      func() time.Time
+
+	files     map[string]*archiveFile
+	manifests map[string]*manifest
+}
+
+type archiveFile struct {
+	node    string
+	channel string
+	f       *os.File
+	opened  time.Time
+	entry   manifestEntry
+}
+
+func newArchive(dir string, maxSize int64, maxAge time.Duration, compress bool, p printer.Interface) *archive {
+	return &archive{
+		dir:       dir,
+		maxSize:   maxSize,
+		maxAge:    maxAge,
+		compress:  compress,
+		printer:   p,
+		now:       time.Now,
+		files:     make(map[string]*archiveFile),
+		manifests: make(map[string]*manifest),
+	}
+}
+
+func (a *archive) Write(item outputItem) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	node, channel := item.source()
+	if channel == "" {
+		channel = unnamedChannel
+	}
+	key := node + "/" + channel
+
+	af, ok := a.files[key]
+	if ok && a.full(af) {
+		if err := a.rotate(af); err != nil {
+			return err
+		}
+		ok = false
+	}
+	if !ok {
+		var err error
+		if af, err = a.open(node, channel); err != nil {
+			return err
+		}
+		a.files[key] = af
+	}
+
+	w := &countingWriter{w: af.f}
+	if err := a.printer.Print(w, item); err != nil {
+		return fmt.Errorf("failed to archive item: %w", err)
+	}
+	if af.entry.Items == 0 {
+		af.entry.From = item.at()
+	}
+	af.entry.To = item.at()
+	af.entry.Items++
+	af.entry.Bytes += w.n
+	return nil
+}
+
+func (a *archive) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var errs []error
+	for key, af := range a.files {
+		errs = append(errs, a.rotate(af))
+		delete(a.files, key)
+	}
+	return errors.Join(errs...)
+}
+
+// rotateExpired rotates the files that have been open for longer than
+// maxAge, so files of idle channels are rotated as well.
+func (a *archive) rotateExpired() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var errs []error
+	for key, af := range a.files {
+		if a.maxAge > 0 && a.now().Sub(af.opened) >= a.maxAge {
+			errs = append(errs, a.rotate(af))
+			delete(a.files, key)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// watch calls rotateExpired periodically until the returned function is
+// called.
+func (a *archive) watch(interval time.Duration) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := a.rotateExpired(); err != nil {
+					log.Printf("failed to rotate archive files: %s", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func (a *archive) full(af *archiveFile) bool {
+	if a.maxSize > 0 && af.entry.Bytes >= a.maxSize {
+		return true
+	}
+	return a.maxAge > 0 && a.now().Sub(af.opened) >= a.maxAge
+}
+
+func (a *archive) open(node, channel string) (*archiveFile, error) {
+	dir := filepath.Join(a.dir, fileName(node))
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	path := filepath.Join(dir, fileName(channel)+".log")
+	if err := a.recover(node, channel, path); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive file: %w", err)
+	}
+	now := a.now()
+	active := manifestEntry{File: filepath.Base(path), Channel: channel, From: now, To: now, Active: true}
+	if err := a.record(node, dir, channel, active); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &archiveFile{
+		node:    node,
+		channel: channel,
+		f:       f,
+		opened:  now,
+		entry:   manifestEntry{Channel: channel},
+	}, nil
+}
+
+// recover rotates a file left behind by a previous run that ended without
+// rotating it, so its items are kept.
+func (a *archive) recover(node, channel, path string) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && info.Size() == 0) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open archive file: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open archive file: %w", err)
+	}
+	return a.rotate(&archiveFile{
+		node:    node,
+		channel: channel,
+		f:       f,
+		opened:  info.ModTime(),
+		entry: manifestEntry{
+			Channel:   channel,
+			From:      info.ModTime(),
+			To:        info.ModTime(),
+			Bytes:     info.Size(),
+			Recovered: true,
+		},
+	})
+}
+
+// rotate closes the current file of a node and channel, moves it to a name
+// carrying the time of its first item and records it in the manifest.
+func (a *archive) rotate(af *archiveFile) error {
+	path := af.f.Name()
+	if err := af.f.Close(); err != nil {
+		return fmt.Errorf("failed to close archive file: %w", err)
+	}
+	if af.entry.Items == 0 && !af.entry.Recovered {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		return a.record(af.node, filepath.Dir(path), af.channel)
+	}
+
+	dir := filepath.Dir(path)
+	base := fmt.Sprintf("%s-%s", fileName(af.channel), af.entry.From.UTC().Format("20060102T150405Z"))
+	ext := ".log"
+	if a.compress {
+		ext += ".gz"
+	}
+	name := base + ext
+	for i := 1; exists(filepath.Join(dir, name)); i++ {
+		name = fmt.Sprintf("%s.%d%s", base, i, ext)
+	}
+
+	var err error
+	if a.compress {
+		err = gzipFile(path, filepath.Join(dir, name))
+	} else {
+		err = os.Rename(path, filepath.Join(dir, name))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to rotate archive file: %w", err)
+	}
+
+	af.entry.File = name
+	return a.record(af.node, dir, af.channel, af.entry)
+}
+
+// record replaces the entry of the active file of a channel in the manifest
+// of a node with the given entries and rewrites it.
+func (a *archive) record(node, dir, channel string, entries ...manifestEntry) error {
+	m, ok := a.manifests[node]
+	if !ok {
+		m = &manifest{Node: node}
+		if b, err := os.ReadFile(filepath.Join(dir, manifestFile)); err == nil {
+			if err := json.Unmarshal(b, m); err != nil {
+				return fmt.Errorf("failed to read manifest: %w", err)
+			}
+		}
+		a.manifests[node] = m
+	}
+	files := m.Files[:0]
+	for _, e := range m.Files {
+		if !e.Active || e.Channel != channel {
+			files = append(files, e)
+		}
+	}
+	m.Files = append(files, entries...)
+	sort.SliceStable(m.Files, func(i, j int) bool {
+		return m.Files[i].From.Before(m.Files[j].From)
+	})
+
+	b, err := json.MarshalIndent(m, "", "    ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, manifestFile+".tmp")
+	if err := os.WriteFile(tmp, append(b, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return os.Rename(tmp, filepath.Join(dir, manifestFile))
+}
+
+func gzipFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// fileName turns node addresses and channel names into portable file names.
+func fileName(s string) string {
+	return unsafeFileChars.ReplaceAllString(s, "_")
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,206 @@
+package observe
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aurae-runtime/ae/pkg/cli/printer"
+)
+
+func TestArchiveRotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Unix(1673085600, 0).UTC()
+	a := newArchive(dir, 64, 0, false, printer.NewText())
+
+	for i := 0; i < 4; i++ {
+		item := outputLogItem{Node: "10.0.0.1", Channel: "stdout", Timestamp: start.Add(time.Duration(i) * time.Second), Line: strings.Repeat("x", 20)}
+		if err := a.Write(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := a.Write(outputSignal{Node: "10.0.0.1", Timestamp: start, Signal: 9, Name: "SIGKILL"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := readManifest(t, filepath.Join(dir, "10.0.0.1"))
+	if len(m.Files) != 3 {
+		t.Fatalf("want 3 archived files, got %+v", m.Files)
+	}
+	var items int
+	for _, f := range m.Files {
+		if _, err := os.Stat(filepath.Join(dir, "10.0.0.1", f.File)); err != nil {
+			t.Fatalf("want archived file %q: %v", f.File, err)
+		}
+		if f.Channel == "stdout" {
+			items += f.Items
+		}
+	}
+	if items != 4 {
+		t.Fatalf("want 4 archived stdout items, got %d", items)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "10.0.0.1", "stdout.log")); !os.IsNotExist(err) {
+		t.Fatalf("want no open file left after close, got %v", err)
+	}
+}
+
+func TestArchiveRotatesByAgeAndCompresses(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Unix(1673085600, 0).UTC()
+	now := start
+	a := newArchive(dir, 0, time.Minute, true, printer.NewText())
+	a.now = func() time.Time { return now }
+
+	if err := a.Write(outputLogItem{Node: "fe80::1", Channel: "auraed", Timestamp: start, Line: "first"}); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(2 * time.Minute)
+	if err := a.Write(outputLogItem{Node: "fe80::1", Channel: "auraed", Timestamp: now, Line: "second"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	node := filepath.Join(dir, "fe80__1")
+	m := readManifest(t, node)
+	if len(m.Files) != 2 || m.Node != "fe80::1" {
+		t.Fatalf("want 2 archived files for node fe80::1, got %+v", m)
+	}
+	if want := "auraed-20230107T100000Z.log.gz"; m.Files[0].File != want {
+		t.Fatalf("want first file %q, got %q", want, m.Files[0].File)
+	}
+	if !m.Files[0].From.Equal(start) || !m.Files[0].To.Equal(start) {
+		t.Fatalf("unexpected time range %v - %v", m.Files[0].From, m.Files[0].To)
+	}
+
+	f, err := os.Open(filepath.Join(node, m.Files[0].File))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "first") {
+		t.Fatalf("want archived line, got %q", b)
+	}
+}
+
+func readManifest(t *testing.T, dir string) manifest {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestArchiveRecoversUnrotatedFile(t *testing.T) {
+	dir := t.TempDir()
+	node := filepath.Join(dir, "10.0.0.1")
+	if err := os.MkdirAll(node, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	left := filepath.Join(node, "stdout.log")
+	if err := os.WriteFile(left, []byte("from a crashed run\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Unix(1673085600, 0).UTC()
+	if err := os.Chtimes(left, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	a := newArchive(dir, 0, 0, false, printer.NewText())
+	if err := a.Write(outputLogItem{Node: "10.0.0.1", Channel: "stdout", Timestamp: mtime.Add(time.Hour), Line: "new"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := readManifest(t, node)
+	if len(m.Files) != 2 || !m.Files[0].Recovered || m.Files[1].Items != 1 {
+		t.Fatalf("want recovered and new file, got %+v", m.Files)
+	}
+	b, err := os.ReadFile(filepath.Join(node, m.Files[0].File))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "from a crashed run\n" {
+		t.Fatalf("want items of the previous run, got %q", b)
+	}
+}
+
+func TestArchiveRotatesIdleFiles(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Unix(1673085600, 0).UTC()
+	now := start
+	a := newArchive(dir, 0, time.Minute, false, printer.NewText())
+	a.now = func() time.Time { return now }
+
+	if err := a.Write(outputLogItem{Node: "10.0.0.1", Channel: "stdout", Timestamp: start, Line: "only"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.rotateExpired(); err != nil {
+		t.Fatal(err)
+	}
+	if m := readManifest(t, filepath.Join(dir, "10.0.0.1")); len(m.Files) != 1 || !m.Files[0].Active {
+		t.Fatalf("want no rotation before max age, got %+v", m.Files)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if err := a.rotateExpired(); err != nil {
+		t.Fatal(err)
+	}
+	if m := readManifest(t, filepath.Join(dir, "10.0.0.1")); len(m.Files) != 1 || m.Files[0].Items != 1 || m.Files[0].Active {
+		t.Fatalf("want idle file rotated, got %+v", m.Files)
+	}
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestArchiveListsActiveFile(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Unix(1673085600, 0).UTC()
+	a := newArchive(dir, 0, 0, false, printer.NewText())
+	a.now = func() time.Time { return start }
+
+	if err := a.Write(outputLogItem{Node: "10.0.0.1", Timestamp: start, Line: "no channel"}); err != nil {
+		t.Fatal(err)
+	}
+	node := filepath.Join(dir, "10.0.0.1")
+	m := readManifest(t, node)
+	if len(m.Files) != 1 || !m.Files[0].Active || m.Files[0].File != "unnamed.log" || m.Files[0].Channel != unnamedChannel {
+		t.Fatalf("want active file of the unnamed channel, got %+v", m.Files)
+	}
+	if _, err := os.Stat(filepath.Join(node, m.Files[0].File)); err != nil {
+		t.Fatalf("want active file %q: %v", m.Files[0].File, err)
+	}
+
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	m = readManifest(t, node)
+	if len(m.Files) != 1 || m.Files[0].Active || m.Files[0].File != "unnamed-20230107T100000Z.log" {
+		t.Fatalf("want active file replaced by the rotated one, got %+v", m.Files)
+	}
+}
@@ -0,0 +1,116 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package observe
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	minReconnectDelay = 500 * time.Millisecond
+	maxReconnectDelay = 30 * time.Second
+)
+
+// backoff yields exponentially growing delays between reconnects.
+type backoff struct {
+	min  time.Duration
+	max  time.Duration
+	next time.Duration
+}
+
+func newBackoff(minDelay, maxDelay time.Duration) *backoff {
+	return &backoff{min: minDelay, max: maxDelay, next: minDelay}
+}
+
+func (b *backoff) Next() time.Duration {
+	d := b.next
+	b.next *= 2
+	if b.next > b.max {
+		b.next = b.max
+	}
+	return d
+}
+
+func (b *backoff) Reset() {
+	b.next = b.min
+}
+
+// resumeTracker remembers the newest log items delivered for a node, so that
+// items replayed by a reconnected stream are not printed twice. LogItem
+// timestamps have a resolution of one second, so the lines seen within the
+// newest second are remembered as well.
+type resumeTracker struct {
+	last     time.Time
+	lines    map[string]struct{}
+	resuming bool
+}
+
+// Resume marks the start of a reconnected stream.
+func (r *resumeTracker) Resume() {
+	r.resuming = !r.last.IsZero()
+}
+
+// Skip reports whether item has already been delivered by a previous stream
+// and records it as delivered otherwise.
+func (r *resumeTracker) Skip(item outputLogItem) bool {
+	if r.resuming {
+		if item.Timestamp.Before(r.last) {
+			return true
+		}
+		if item.Timestamp.Equal(r.last) {
+			if _, ok := r.lines[item.Line]; ok {
+				return true
+			}
+		} else {
+			r.resuming = false
+		}
+	}
+
+	if item.Timestamp.After(r.last) {
+		r.last = item.Timestamp
+		r.lines = make(map[string]struct{})
+	}
+	r.lines[item.Line] = struct{}{}
+	return false
+}
+
+// reconnectMarker is printed in place of a log item whenever the stream of
+// a node has been reopened.
+func reconnectMarker(node string, cause error) outputLogItem {
+	return outputLogItem{
+		Node:      node,
+		Channel:   "ae",
+		Timestamp: time.Now().UTC(),
+		Level:     "WARN",
+		Line:      fmt.Sprintf("--- reconnected to %s after: %s ---", node, cause),
+	}
+}
@@ -0,0 +1,189 @@
+package observe
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	observev0 "github.com/aurae-runtime/ae/pkg/api/v0/observe"
+)
+
+func TestBackoff(t *testing.T) {
+	b := newBackoff(time.Second, 5*time.Second)
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := b.Next(); got != w {
+			t.Fatalf("want delay %d to be %s, got %s", i, w, got)
+		}
+	}
+	b.Reset()
+	if got := b.Next(); got != time.Second {
+		t.Fatalf("want delay %s after reset, got %s", time.Second, got)
+	}
+}
+
+func TestResumeTracker(t *testing.T) {
+	start := time.Unix(1673085600, 0)
+	r := &resumeTracker{}
+
+	// identical lines within the same second are delivered while not resuming
+	for _, line := range []string{"a", "b", "b"} {
+		if r.Skip(outputLogItem{Timestamp: start, Line: line}) {
+			t.Fatalf("want line %q to be delivered", line)
+		}
+	}
+
+	r.Resume()
+	ts := []struct {
+		item     outputLogItem
+		wantskip bool
+	}{
+		{outputLogItem{Timestamp: start.Add(-time.Second), Line: "old"}, true},
+		{outputLogItem{Timestamp: start, Line: "b"}, true},
+		{outputLogItem{Timestamp: start, Line: "c"}, false},
+		{outputLogItem{Timestamp: start.Add(time.Second), Line: "d"}, false},
+		{outputLogItem{Timestamp: start.Add(time.Second), Line: "d"}, false},
+	}
+	for _, tt := range ts {
+		if got := r.Skip(tt.item); got != tt.wantskip {
+			t.Fatalf("[%s] want skip %t, got %t", tt.item.Line, tt.wantskip, got)
+		}
+	}
+}
+
+// fakeDaemonLogStream returns its items followed by err. Without err, it
+// blocks until its context is canceled.
+type fakeDaemonLogStream struct {
+	grpc.ClientStream
+	ctx   context.Context
+	items []*observev0.LogItem
+	err   error
+}
+
+func (s *fakeDaemonLogStream) Recv() (*observev0.GetAuraeDaemonLogStreamResponse, error) {
+	if len(s.items) == 0 {
+		if s.err == nil {
+			<-s.ctx.Done()
+			return nil, s.ctx.Err()
+		}
+		return nil, s.err
+	}
+	item := s.items[0]
+	s.items = s.items[1:]
+	return &observev0.GetAuraeDaemonLogStreamResponse{Item: item}, nil
+}
+
+type fakeSignalsStream struct {
+	grpc.ClientStream
+	signals []*observev0.Signal
+}
+
+func (s *fakeSignalsStream) Recv() (*observev0.GetPosixSignalsStreamResponse, error) {
+	if len(s.signals) == 0 {
+		return nil, io.EOF
+	}
+	sig := s.signals[0]
+	s.signals = s.signals[1:]
+	return &observev0.GetPosixSignalsStreamResponse{Signal: sig}, nil
+}
+
+type fakeObserve struct {
+	streams    []*fakeDaemonLogStream
+	signals    *fakeSignalsStream
+	signalsReq *observev0.GetPosixSignalsStreamRequest
+}
+
+func (f *fakeObserve) GetAuraeDaemonLogStream(ctx context.Context, _ *observev0.GetAuraeDaemonLogStreamRequest) (observev0.ObserveService_GetAuraeDaemonLogStreamClient, error) {
+	s := f.streams[0]
+	f.streams = f.streams[1:]
+	s.ctx = ctx
+	return s, nil
+}
+
+func (f *fakeObserve) GetSubProcessStream(context.Context, *observev0.GetSubProcessStreamRequest) (observev0.ObserveService_GetSubProcessStreamClient, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeObserve) GetPosixSignalsStream(_ context.Context, req *observev0.GetPosixSignalsStreamRequest) (observev0.ObserveService_GetPosixSignalsStreamClient, error) {
+	f.signalsReq = req
+	return f.signals, nil
+}
+
+func TestStreamHostResume(t *testing.T) {
+	obs := &fakeObserve{streams: []*fakeDaemonLogStream{
+		{
+			items: []*observev0.LogItem{{Line: "a", Timestamp: 1}, {Line: "b", Timestamp: 2}},
+			err:   errors.New("connection reset"),
+		},
+		{
+			items: []*observev0.LogItem{{Line: "b", Timestamp: 2}, {Line: "c", Timestamp: 3}},
+			err:   io.EOF,
+		},
+	}}
+
+	o := &option{ctx: context.Background(), logtype: "daemon"}
+	tracker := &resumeTracker{}
+	items := make(chan outputItem, 10)
+
+	delivered, err := o.streamHost("10.0.0.1", obs, tracker, items, func() {})
+	if !delivered || err == nil {
+		t.Fatalf("want delivered items and an error, got %t and %v", delivered, err)
+	}
+	tracker.Resume()
+	delivered, err = o.streamHost("10.0.0.1", obs, tracker, items, func() {})
+	if !delivered || err != nil {
+		t.Fatalf("want delivered items and no error, got %t and %v", delivered, err)
+	}
+	close(items)
+
+	var got []string
+	for item := range items {
+		got = append(got, item.(outputLogItem).Line)
+	}
+	want := []string{"a", "b", "c"}
+	if len(got) != len(want) {
+		t.Fatalf("want lines %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("want lines %v, got %v", want, got)
+		}
+	}
+}
+
+func TestStreamsReleaseSlotOnceOpened(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	o := &option{ctx: ctx, logtype: "daemon", slots: make(chan struct{}, 1)}
+
+	// the stream never ends, as daemon streams without --until
+	obs := &fakeObserve{streams: []*fakeDaemonLogStream{{}}}
+	release, ok := o.acquire()
+	if !ok {
+		t.Fatal("want slot to be acquired")
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		o.streamHost("10.0.0.1", obs, &resumeTracker{}, make(chan outputItem), release)
+	}()
+
+	acquired := make(chan struct{})
+	go func() {
+		if release, ok := o.acquire(); ok {
+			release()
+			close(acquired)
+		}
+	}()
+	select {
+	case <-acquired:
+	case <-time.After(2 * time.Second):
+		t.Fatal("want slot to be released once the stream is open")
+	}
+	cancel()
+	<-done
+}
@@ -0,0 +1,387 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package observe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	forwardInterval   = time.Second
+	forwardRetries    = 5
+	forwardTimeout    = 10 * time.Second
+	minForwardBackoff = 200 * time.Millisecond
+	maxForwardBackoff = 5 * time.Second
+)
+
+// forwardRecord is the flattened form of an observed item pushed to a log
+// backend.
+type forwardRecord struct {
+	node      string
+	channel   string
+	cell      string
+	level     string
+	timestamp time.Time
+	line      string
+}
+
+func toForwardRecord(item outputItem) forwardRecord {
+	switch i := item.(type) {
+	case outputLogItem:
+		return forwardRecord{node: i.Node, channel: i.Channel, level: i.Level, timestamp: i.Timestamp, line: i.Line}
+	case outputSignal:
+		return forwardRecord{
+			node:      i.Node,
+			channel:   "signals",
+			cell:      i.Cell,
+			timestamp: i.Timestamp,
+			line:      fmt.Sprintf("%s(%d) pid=%d", i.Name, i.Signal, i.ProcessID),
+		}
+	default:
+		node, channel := item.source()
+		return forwardRecord{node: node, channel: channel, timestamp: item.at(), line: fmt.Sprint(item)}
+	}
+}
+
+// forwarder pushes observed items to a Loki push API or an OTLP/HTTP logs
+// endpoint. Items are queued in a bounded buffer and sent in batches from a
+// background goroutine; when the buffer is full, new items are dropped rather
+// than blocking the output. Failed pushes are retried with an exponential
+// backoff on network errors, 429 and 5xx responses, until ctx is canceled.
+type forwarder struct {
+	ctx        context.Context
+	url        string
+	format     string
+	client     *http.Client
+	batchSize  int
+	interval   time.Duration
+	retries    int
+	retryDelay time.Duration
+
+	records chan forwardRecord
+	done    chan struct{}
+	dropped int
+	failed  int
+	err     error
+}
+
+// forwardFormat returns the push format of url. Without an explicit format,
+// URLs ending in /v1/logs are treated as OTLP/HTTP and all others as Loki.
+func forwardFormat(rawURL, format string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid --forward url %q", rawURL)
+	}
+	switch format {
+	case "loki", "otlp":
+		return format, nil
+	case "":
+		if strings.HasSuffix(u.Path, "/v1/logs") {
+			return "otlp", nil
+		}
+		return "loki", nil
+	default:
+		return "", fmt.Errorf("unknown --forward-format %q, must be 'loki' or 'otlp'", format)
+	}
+}
+
+// forwardURL completes URLs without a path with the default push path of
+// the format.
+func forwardURL(rawURL, format string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || strings.Trim(u.Path, "/") != "" {
+		return rawURL
+	}
+	if format == "otlp" {
+		u.Path = "/v1/logs"
+	} else {
+		u.Path = "/loki/api/v1/push"
+	}
+	return u.String()
+}
+
+func newForwarder(ctx context.Context, rawURL, format string, batchSize, bufferSize int) *forwarder {
+	f := &forwarder{
+		ctx:        ctx,
+		url:        forwardURL(rawURL, format),
+		format:     format,
+		client:     &http.Client{Timeout: forwardTimeout},
+		batchSize:  batchSize,
+		interval:   forwardInterval,
+		retries:    forwardRetries,
+		retryDelay: minForwardBackoff,
+		records:    make(chan forwardRecord, bufferSize),
+		done:       make(chan struct{}),
+	}
+	go f.run()
+	return f
+}
+
+func (f *forwarder) Write(item outputItem) error {
+	select {
+	case f.records <- toForwardRecord(item):
+	default:
+		f.dropped++
+	}
+	return nil
+}
+
+// Close flushes the buffered items and waits for the last push to finish.
+func (f *forwarder) Close() error {
+	close(f.records)
+	<-f.done
+	if f.dropped > 0 {
+		log.Printf("dropped %d items because the forward buffer was full\n", f.dropped)
+	}
+	if f.err != nil {
+		return fmt.Errorf("failed to forward %d items: %w", f.failed, f.err)
+	}
+	return nil
+}
+
+func (f *forwarder) run() {
+	defer close(f.done)
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	var batch []forwardRecord
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := f.push(batch); err != nil {
+			log.Printf("failed to forward %d items to %s: %s\n", len(batch), f.url, err)
+			f.failed += len(batch)
+			f.err = err
+		}
+		batch = nil
+	}
+	for {
+		select {
+		case r, ok := <-f.records:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, r)
+			if len(batch) >= f.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (f *forwarder) push(batch []forwardRecord) error {
+	var body []byte
+	var err error
+	if f.format == "otlp" {
+		body, err = json.Marshal(otlpBody(batch))
+	} else {
+		body, err = json.Marshal(lokiBody(batch))
+	}
+	if err != nil {
+		return err
+	}
+
+	backoff := newBackoff(f.retryDelay, maxForwardBackoff)
+	for attempt := 0; ; attempt++ {
+		retry, err := f.post(body)
+		if err == nil || !retry || attempt >= f.retries {
+			return err
+		}
+		// the batch is dropped once the command is interrupted, so Close
+		// does not wait for the backoff
+		select {
+		case <-time.After(backoff.Next()):
+		case <-f.ctx.Done():
+			return fmt.Errorf("%w, not retried: %s", err, f.ctx.Err())
+		}
+	}
+}
+
+// post sends a single request and reports whether a failure may be retried.
+func (f *forwarder) post(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, f.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(msg))
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// lokiBody groups records into streams by their labels.
+func lokiBody(batch []forwardRecord) lokiPush {
+	var push lokiPush
+	streams := make(map[string]int)
+	for _, r := range batch {
+		labels := map[string]string{"node": r.node, "channel": r.channel}
+		if r.cell != "" {
+			labels["cell"] = r.cell
+		}
+		if r.level != "" {
+			labels["level"] = strings.ToLower(r.level)
+		}
+		key := fmt.Sprint(labels)
+		i, ok := streams[key]
+		if !ok {
+			i = len(push.Streams)
+			streams[key] = i
+			push.Streams = append(push.Streams, lokiStream{Stream: labels})
+		}
+		push.Streams[i].Values = append(push.Streams[i].Values, [2]string{strconv.FormatInt(r.timestamp.UnixNano(), 10), r.line})
+	}
+	return push
+}
+
+type otlpLogs struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         string          `json:"timeUnixNano"`
+	ObservedTimeUnixNano string          `json:"observedTimeUnixNano"`
+	SeverityNumber       int             `json:"severityNumber,omitempty"`
+	SeverityText         string          `json:"severityText,omitempty"`
+	Body                 otlpValue       `json:"body"`
+	Attributes           []otlpAttribute `json:"attributes"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// otlpSeverity maps log levels to OTLP severity numbers.
+var otlpSeverity = map[string]int{
+	"TRACE": 1,
+	"DEBUG": 5,
+	"INFO":  9,
+	"WARN":  13,
+	"ERROR": 17,
+}
+
+// otlpBody groups records into one resource per node.
+func otlpBody(batch []forwardRecord) otlpLogs {
+	observed := strconv.FormatInt(time.Now().UnixNano(), 10)
+	records := make(map[string][]otlpLogRecord)
+	for _, r := range batch {
+		attrs := []otlpAttribute{{Key: "channel", Value: otlpValue{r.channel}}}
+		if r.cell != "" {
+			attrs = append(attrs, otlpAttribute{Key: "cell", Value: otlpValue{r.cell}})
+		}
+		records[r.node] = append(records[r.node], otlpLogRecord{
+			TimeUnixNano:         strconv.FormatInt(r.timestamp.UnixNano(), 10),
+			ObservedTimeUnixNano: observed,
+			SeverityNumber:       otlpSeverity[r.level],
+			SeverityText:         r.level,
+			Body:                 otlpValue{r.line},
+			Attributes:           attrs,
+		})
+	}
+
+	nodes := make([]string, 0, len(records))
+	for node := range records {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	var logs otlpLogs
+	for _, node := range nodes {
+		logs.ResourceLogs = append(logs.ResourceLogs, otlpResourceLogs{
+			Resource: otlpResource{Attributes: []otlpAttribute{
+				{Key: "service.name", Value: otlpValue{"auraed"}},
+				{Key: "host.name", Value: otlpValue{node}},
+			}},
+			ScopeLogs: []otlpScopeLogs{{
+				Scope:      otlpScope{Name: "ae"},
+				LogRecords: records[node],
+			}},
+		})
+	}
+	return logs
+}
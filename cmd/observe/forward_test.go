@@ -0,0 +1,164 @@
+package observe
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type pushRecorder struct {
+	mu       sync.Mutex
+	bodies   [][]byte
+	failures int
+}
+
+func (p *pushRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failures > 0 {
+		p.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	b, _ := io.ReadAll(r.Body)
+	p.bodies = append(p.bodies, b)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestForwardFormat(t *testing.T) {
+	ts := []struct {
+		url, format, want string
+		wanterr           bool
+	}{
+		{url: "http://loki:3100", want: "loki"},
+		{url: "http://collector:4318/v1/logs", want: "otlp"},
+		{url: "http://collector:4318", format: "otlp", want: "otlp"},
+		{url: "http://loki:3100", format: "syslog", wanterr: true},
+		{url: "loki:3100", wanterr: true},
+	}
+	for _, tt := range ts {
+		got, err := forwardFormat(tt.url, tt.format)
+		if tt.wanterr != (err != nil) || got != tt.want {
+			t.Fatalf("[%s %s] want %q (error %t), got %q (%v)", tt.url, tt.format, tt.want, tt.wanterr, got, err)
+		}
+	}
+	if got := forwardURL("http://collector:4318", "otlp"); got != "http://collector:4318/v1/logs" {
+		t.Fatalf("want default otlp path, got %q", got)
+	}
+}
+
+func TestForwardLoki(t *testing.T) {
+	rec := &pushRecorder{failures: 2}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	f := newForwarder(context.Background(), srv.URL, "loki", 2, 10)
+	f.retryDelay = time.Millisecond
+	ts := time.Unix(1673085600, 0)
+	items := []outputItem{
+		outputLogItem{Node: "10.0.0.1", Channel: "stdout", Timestamp: ts, Level: "INFO", Line: "one"},
+		outputLogItem{Node: "10.0.0.1", Channel: "stdout", Timestamp: ts, Level: "INFO", Line: "two"},
+		outputSignal{Node: "10.0.0.2", Timestamp: ts, Signal: 9, Name: "SIGKILL", ProcessID: 7},
+	}
+	for _, item := range items {
+		if err := f.Write(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rec.bodies) != 2 {
+		t.Fatalf("want 2 batches, got %d", len(rec.bodies))
+	}
+	var push lokiPush
+	if err := json.Unmarshal(rec.bodies[0], &push); err != nil {
+		t.Fatal(err)
+	}
+	if len(push.Streams) != 1 || len(push.Streams[0].Values) != 2 {
+		t.Fatalf("want one stream with 2 values, got %+v", push)
+	}
+	labels := push.Streams[0].Stream
+	if labels["node"] != "10.0.0.1" || labels["cell"] != "" || labels["channel"] != "stdout" || labels["level"] != "info" {
+		t.Fatalf("unexpected labels %v", labels)
+	}
+	if v := push.Streams[0].Values[0]; v[0] != "1673085600000000000" || v[1] != "one" {
+		t.Fatalf("unexpected value %v", v)
+	}
+}
+
+func TestForwardOTLP(t *testing.T) {
+	rec := &pushRecorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	f := newForwarder(context.Background(), srv.URL+"/v1/logs", "otlp", 10, 10)
+	if err := f.Write(outputLogItem{Node: "10.0.0.1", Channel: "auraed", Timestamp: time.Unix(1, 0), Level: "ERROR", Line: "boom"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rec.bodies) != 1 {
+		t.Fatalf("want 1 batch, got %d", len(rec.bodies))
+	}
+	var logs otlpLogs
+	if err := json.Unmarshal(rec.bodies[0], &logs); err != nil {
+		t.Fatal(err)
+	}
+	if len(logs.ResourceLogs) != 1 {
+		t.Fatalf("want 1 resource, got %+v", logs)
+	}
+	r := logs.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+	if r.Body.StringValue != "boom" || r.SeverityNumber != 17 || r.TimeUnixNano != "1000000000" {
+		t.Fatalf("unexpected record %+v", r)
+	}
+}
+
+func TestForwardGivesUp(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	f := newForwarder(context.Background(), srv.URL, "loki", 10, 10)
+	if err := f.Write(outputLogItem{Node: "10.0.0.1", Line: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err == nil {
+		t.Fatal("want error for rejected push, got no error")
+	}
+}
+
+func TestForwardStopsRetryingOnCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f := newForwarder(ctx, srv.URL, "loki", 10, 10)
+	f.retryDelay = time.Hour
+	if err := f.Write(outputLogItem{Node: "10.0.0.1", Line: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	done := make(chan error)
+	go func() { done <- f.Close() }()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("want error for dropped batch, got no error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close blocked on the retry backoff")
+	}
+}
@@ -37,39 +37,101 @@ import (
 	"io"
 	"log"
 	"net"
+	"os"
+	ossignal "os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/3th1nk/cidr"
 	"github.com/aurae-runtime/ae/pkg/cli"
 	"github.com/aurae-runtime/ae/pkg/cli/printer"
 	"github.com/aurae-runtime/ae/pkg/client"
 	"github.com/aurae-runtime/ae/pkg/config"
+	"github.com/aurae-runtime/ae/pkg/observe"
+	"github.com/aurae-runtime/ae/pkg/signal"
 	"github.com/spf13/cobra"
 
 	aeCMD "github.com/aurae-runtime/ae/cmd"
 	observev0 "github.com/aurae-runtime/ae/pkg/api/v0/observe"
 )
 
-type outputObserve struct{}
+// outputItem is a single item of an observed stream, such as a log line or
+// a signal.
+type outputItem interface {
+	at() time.Time
+	// source returns the node and channel an item has been observed on.
+	source() (string, string)
+}
+
+type outputLogItem struct {
+	Node      string    `json:"node" yaml:"node"`
+	Channel   string    `json:"channel" yaml:"channel"`
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+	Level     string    `json:"level,omitempty" yaml:"level,omitempty"`
+	Line      string    `json:"line" yaml:"line"`
+}
+
+func (i outputLogItem) String() string {
+	return fmt.Sprintf("%s %s %s", i.Node, i.Timestamp.Format(time.RFC3339), i.Line)
+}
+
+func (i outputLogItem) at() time.Time {
+	return i.Timestamp
+}
+
+func (i outputLogItem) source() (string, string) {
+	return i.Node, i.Channel
+}
 
 type option struct {
 	aeCMD.Option
 	ctx          context.Context
-	ip           string
+	target       string
 	logtype      string
+	pid          int64
+	channel      string
+	cell         string
+	signal       string
+	sortWindow   time.Duration
+	follow       bool
+	since        string
+	until        string
+	sinceTime    time.Time
+	untilTime    time.Time
+	tail         int
+	outputDir    string
+	maxSize      int64
+	maxAge       time.Duration
+	compress     bool
+	forward      string
+	forwardFmt   string
+	forwardBatch int
+	forwardBuf   int
+	parallel     int
+	slots        chan struct{}
 	port         uint16
-	protocol     string
 	verbose      bool
 	writer       io.Writer
-	output       *outputObserve
 	outputFormat *cli.OutputFormat
 }
 
 func (o *option) Complete(args []string) error {
-	log.Println(args)
 	if len(args) != 2 {
-		return errors.New("expected ip address and log type to be passed to this command")
+		return errors.New("expected target and log type to be passed to this command")
 	}
-	o.ip = args[0]
+	o.target = args[0]
 	o.logtype = args[1]
+
+	now := time.Now()
+	var err error
+	if o.sinceTime, err = parseTimeFlag("since", o.since, now); err != nil {
+		return err
+	}
+	if o.untilTime, err = parseTimeFlag("until", o.until, now); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -77,115 +139,548 @@ func (o *option) Validate() error {
 	if err := o.outputFormat.Validate(); err != nil {
 		return err
 	}
-	if len(o.ip) == 0 {
-		return errors.New("ip address must be passed to this command")
+	if len(o.target) == 0 {
+		return errors.New("target must be passed to this command")
+	}
+	if _, err := targetIPs(o.target); err != nil {
+		return err
 	}
-	if ip := net.ParseIP(o.ip); ip == nil {
-		return fmt.Errorf("failed to parse IP %q", o.ip)
+	if o.logtype != "signals" && o.signal != "" {
+		return errors.New("--signal can only be used with 'signals'")
 	}
-	if o.logtype != "daemon" && o.logtype != "subprocesses" {
-		return errors.New("either 'daemon' or 'subprocesses' must be passed to the command")
+	if o.logtype != "signals" && o.cell != "" {
+		// subprocess streams are selected by PID, auraed can not resolve
+		// the processes of a cell
+		return errors.New("--cell can only be used with 'signals', observe the subprocesses of a cell by their --pid")
+	}
+	switch o.logtype {
+	case "daemon":
+		if o.pid != 0 {
+			return errors.New("--pid can only be used with 'subprocesses' or 'signals'")
+		}
+	case "subprocesses":
+		if o.pid <= 0 {
+			return errors.New("a positive --pid must be passed to observe 'subprocesses'")
+		}
+		if _, err := channelType(o.channel); err != nil {
+			return err
+		}
+	case "signals":
+		if o.pid < 0 {
+			return errors.New("--pid must not be negative")
+		}
+		if o.signal != "" {
+			if _, err := signal.Parse(o.signal); err != nil {
+				return err
+			}
+		}
+	default:
+		return errors.New("either 'daemon', 'subprocesses' or 'signals' must be passed to the command")
+	}
+	if o.sortWindow < 0 {
+		return errors.New("--sort-window must not be negative")
+	}
+	if o.parallel <= 0 {
+		return errors.New("--parallel must be positive")
+	}
+	if !o.sinceTime.IsZero() && !o.untilTime.IsZero() && o.untilTime.Before(o.sinceTime) {
+		return errors.New("--until must not be before --since")
+	}
+	if o.tail < -1 {
+		return errors.New("--tail must not be negative")
+	}
+	if o.maxSize < 0 || o.maxAge < 0 {
+		return errors.New("--max-size and --max-age must not be negative")
+	}
+	if o.outputDir == "" && (o.maxSize != 0 || o.maxAge != 0 || o.compress) {
+		return errors.New("--max-size, --max-age and --compress require --output-dir")
+	}
+	if o.forward != "" {
+		if _, err := forwardFormat(o.forward, o.forwardFmt); err != nil {
+			return err
+		}
+		if o.forwardBatch <= 0 || o.forwardBuf <= 0 {
+			return errors.New("--forward-batch-size and --forward-buffer must be positive")
+		}
 	}
 	return nil
 }
 
 func (o *option) Execute(ctx context.Context) error {
+	ips, err := targetIPs(o.target)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 	o.ctx = ctx
-	o.output = &outputObserve{}
 
-	o.protocol = "tcp4"
-	if net.ParseIP(o.ip).To4() == nil {
-		o.protocol = "tcp6"
+	items := make(chan outputItem)
+	errs := make(chan error, len(ips))
+	o.slots = make(chan struct{}, o.parallel)
+	var wg sync.WaitGroup
+	for _, ip := range ips {
+		wg.Add(1)
+		go func(ip string) {
+			defer wg.Done()
+			if err := o.observeHost(ip, items); err != nil {
+				errs <- fmt.Errorf("%s: %w", ip, err)
+			}
+		}(ip)
 	}
-	o.observeHost(o.ip)
-	return o.outputFormat.ToPrinter().Print(o.writer, o.output)
+	go func() {
+		wg.Wait()
+		close(items)
+		close(errs)
+	}()
+
+	var sinks []sink
+	stopWatch := func() {}
+	if o.outputDir != "" {
+		arch := newArchive(o.outputDir, o.maxSize*1024*1024, o.maxAge, o.compress, o.outputFormat.ToPrinter())
+		if o.maxAge > 0 {
+			interval := o.maxAge / 4
+			if interval > time.Minute {
+				interval = time.Minute
+			}
+			if interval < time.Millisecond {
+				interval = time.Millisecond
+			}
+			stopWatch = arch.watch(interval)
+		}
+		sinks = append(sinks, arch)
+	}
+	if o.forward != "" {
+		format, err := forwardFormat(o.forward, o.forwardFmt)
+		if err != nil {
+			return err
+		}
+		sinks = append(sinks, newForwarder(ctx, o.forward, format, o.forwardBatch, o.forwardBuf))
+	}
+
+	err = o.print(items, sinks)
+	stopWatch()
+	for _, s := range sinks {
+		if cerr := s.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	var all []error
+	for err := range errs {
+		log.Printf("failed to observe %s", err)
+		all = append(all, err)
+	}
+	return errors.Join(all...)
 }
 
 func (o *option) SetWriter(writer io.Writer) {
 	o.writer = writer
 }
 
-func (o option) observeHost(ip_str string) bool {
+// print writes the merged items of all nodes to the output and all sinks.
+// With a sort window, items are held back for the duration of the window and
+// released in timestamp order. With --tail, only the last items logged before
+// the start of the command are printed for each node, once the node delivers
+// a live item or its replay has been idle for tailIdle.
+func (o *option) print(items <-chan outputItem, sinks []sink) error {
+	p := o.outputFormat.ToPrinter()
+	emit := func(item outputItem) error {
+		if err := p.Print(o.writer, item); err != nil {
+			return err
+		}
+		for _, s := range sinks {
+			if err := s.Write(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var buf *sortBuffer
+	if o.sortWindow > 0 {
+		buf = newSortBuffer(o.sortWindow)
+	}
+	var tail *tailBuffer
+	if o.tail >= 0 {
+		tail = newTailBuffer(o.tail, time.Now())
+	}
+
+	accept := func(ready []outputItem) error {
+		for _, item := range ready {
+			if buf != nil {
+				buf.Push(item, time.Now())
+				continue
+			}
+			if err := emit(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	flush := func(now time.Time) error {
+		for _, item := range buf.Release(now) {
+			if err := emit(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	finish := func() error {
+		if tail != nil {
+			if err := accept(tail.Flush()); err != nil {
+				return err
+			}
+		}
+		if buf != nil {
+			return flush(time.Now().Add(o.sortWindow))
+		}
+		return nil
+	}
+	add := func(item outputItem) error {
+		if tail != nil {
+			return accept(tail.Add(item))
+		}
+		return accept([]outputItem{item})
+	}
+
+	if buf == nil && tail == nil {
+		for item := range items {
+			if err := add(item); err != nil {
+				return err
+			}
+		}
+		return finish()
+	}
+
+	tick := tailIdle / 4
+	if buf != nil && o.sortWindow/4 < tick {
+		tick = o.sortWindow / 4
+	}
+	if tick < time.Millisecond {
+		tick = time.Millisecond
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case item, ok := <-items:
+			if !ok {
+				return finish()
+			}
+			if err := add(item); err != nil {
+				return err
+			}
+		case now := <-ticker.C:
+			if tail != nil {
+				if err := accept(tail.Release(now)); err != nil {
+					return err
+				}
+			}
+			if buf != nil {
+				if err := flush(now); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// observeHost streams the log items of a single node into items. With
+// --follow, broken streams are reopened with an exponential backoff and items
+// delivered before the reconnect are skipped.
+func (o *option) observeHost(ip_str string, items chan<- outputItem) error {
+	protocol := "tcp4"
+	if net.ParseIP(ip_str).To4() == nil {
+		protocol = "tcp6"
+	}
+
 	if o.verbose {
-		log.Printf("connecting to %s:%d using protocol %s\n", ip_str, o.port, o.protocol)
+		log.Printf("connecting to %s:%d using protocol %s\n", ip_str, o.port, protocol)
 	}
 
-	c, err := client.New(o.ctx, config.WithSystem(config.System{Protocol: o.protocol, Socket: net.JoinHostPort(ip_str, fmt.Sprintf("%d", o.port))}))
+	c, err := client.New(o.ctx, config.WithSystem(config.System{Protocol: protocol, Socket: net.JoinHostPort(ip_str, fmt.Sprintf("%d", o.port))}))
 	if err != nil {
-		log.Fatalf("failed to create client: %s", err)
-		return false
+		return fmt.Errorf("failed to create client: %w", err)
 	}
 
 	obs, err := c.Observe()
 	if err != nil {
-		log.Fatalf("failed to dial Observe service: %s", err)
-		return false
+		return fmt.Errorf("failed to dial Observe service: %w", err)
 	}
 
-	// TODO: handle output format
+	tracker := &resumeTracker{}
+	backoff := newBackoff(minReconnectDelay, maxReconnectDelay)
+	for {
+		release, ok := o.acquire()
+		if !ok {
+			return nil
+		}
+		delivered, err := o.streamHost(ip_str, obs, tracker, items, release)
+		release()
+		if o.ctx.Err() != nil || errors.Is(err, errUntilPassed) {
+			return nil
+		}
+		if !o.follow {
+			return err
+		}
+		if delivered {
+			backoff.Reset()
+		}
+		if err == nil {
+			err = io.EOF
+		}
+
+		delay := backoff.Next()
+		if o.verbose {
+			log.Printf("stream of %s ended: %s. reconnecting in %s\n", ip_str, err, delay)
+		}
+		select {
+		case <-time.After(delay):
+		case <-o.ctx.Done():
+			return nil
+		}
+
+		tracker.Resume()
+		if !o.send(items, reconnectMarker(ip_str, err)) {
+			return nil
+		}
+	}
+}
+
+// streamHost opens a single stream and forwards its items until the stream
+// ends. It reports whether any new item has been delivered.
+func (o *option) streamHost(ip_str string, obs observe.Observe, tracker *resumeTracker, items chan<- outputItem, opened func()) (bool, error) {
+	if o.logtype == "signals" {
+		return o.streamSignals(ip_str, obs, items, opened)
+	}
+
+	ctx, touch, cancel := o.streamContext(untilGrace)
+	defer cancel()
+
+	var recv func() (*observev0.LogItem, error)
 	switch o.logtype {
 	case "daemon":
-		// TODO: request parameters
-		req := observev0.GetAuraeDaemonLogStreamRequest{} // TODO
-		stream, err := obs.GetAuraeDaemonLogStream(o.ctx, &req)
+		req := observev0.GetAuraeDaemonLogStreamRequest{}
+		stream, err := obs.GetAuraeDaemonLogStream(ctx, &req)
 		if err != nil {
-			log.Fatalf("%s", err)
-			return false
+			return false, err
 		}
-		for {
+		recv = func() (*observev0.LogItem, error) {
 			resp, err := stream.Recv()
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				log.Fatalf("%s", err)
-				return false
-			}
-			if _, err := o.writer.Write([]byte(resp.Item.Line)); err != nil {
-				log.Fatalf("%s", err)
-				return false
-			}
+			return resp.GetItem(), err
 		}
 	case "subprocesses":
-		// TODO: request parameters
-		req := observev0.GetSubProcessStreamRequest{} // TODO
-		stream, err := obs.GetSubProcessStream(o.ctx, &req)
+		channel, err := channelType(o.channel)
 		if err != nil {
-			log.Fatalf("%s", err)
-			return false
+			return false, err
 		}
-		for {
+		req := observev0.GetSubProcessStreamRequest{
+			ChannelType: channel,
+			ProcessId:   o.pid,
+		}
+		stream, err := obs.GetSubProcessStream(ctx, &req)
+		if err != nil {
+			return false, err
+		}
+		recv = func() (*observev0.LogItem, error) {
 			resp, err := stream.Recv()
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				log.Fatalf("%s", err)
-				return false
-			}
-			if _, err := o.writer.Write([]byte(resp.Item.Line)); err != nil {
-				log.Fatalf("%s", err)
-				return false
-			}
+			return resp.GetItem(), err
+		}
+	}
+
+	opened()
+
+	delivered := false
+	for {
+		item, err := recv()
+		if err == io.EOF {
+			return delivered, nil
+		}
+		if err != nil {
+			return delivered, o.untilErr(ctx, err)
+		}
+		touch()
+		out := toOutputLogItem(ip_str, item)
+		if !o.untilTime.IsZero() && out.Timestamp.After(o.untilTime) {
+			return delivered, errUntilPassed
+		}
+		if out.Timestamp.Before(o.sinceTime) {
+			continue
+		}
+		if tracker.Skip(out) {
+			continue
+		}
+		if !o.send(items, out) {
+			return delivered, nil
+		}
+		delivered = true
+	}
+}
+
+// acquire waits for one of the --parallel slots limiting the streams being
+// opened at the same time. Established streams do not hold a slot, so all
+// nodes are observed at once. release may be called more than once.
+func (o *option) acquire() (release func(), ok bool) {
+	if o.slots == nil {
+		return func() {}, true
+	}
+	select {
+	case o.slots <- struct{}{}:
+	case <-o.ctx.Done():
+		return nil, false
+	}
+	var once sync.Once
+	return func() { once.Do(func() { <-o.slots }) }, true
+}
+
+// send forwards item unless the command has been canceled.
+func (o *option) send(items chan<- outputItem, item outputItem) bool {
+	select {
+	case items <- item:
+		return true
+	case <-o.ctx.Done():
+		return false
+	}
+}
+
+// maxTargets is the maximum number of nodes a single command observes.
+const maxTargets = 4096
+
+// targetIPs expands a target into the IP addresses of the nodes to observe.
+// A target is either a single IP, a comma separated list of IPs or a CIDR.
+func targetIPs(target string) ([]string, error) {
+	if strings.Contains(target, "/") {
+		c, err := cidr.Parse(target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CIDR %q: %w", target, err)
 		}
+		if n := c.IPCount(); !n.IsInt64() || n.Int64() > maxTargets {
+			return nil, fmt.Errorf("CIDR %q contains %s addresses, at most %d nodes can be observed", target, n, maxTargets)
+		}
+		var ips []string
+		c.Each(func(ip string) bool {
+			ips = append(ips, ip)
+			return true
+		})
+		return ips, nil
+	}
+
+	var ips []string
+	for _, ip := range strings.Split(target, ",") {
+		ip = strings.TrimSpace(ip)
+		if net.ParseIP(ip) == nil {
+			return nil, fmt.Errorf("failed to parse IP %q", ip)
+		}
+		ips = append(ips, ip)
+	}
+	if len(ips) > maxTargets {
+		return nil, fmt.Errorf("%d addresses passed, at most %d nodes can be observed", len(ips), maxTargets)
 	}
+	return ips, nil
+}
+
+func channelType(channel string) (observev0.LogChannelType, error) {
+	switch channel {
+	case "stdout":
+		return observev0.LogChannelType_LOG_CHANNEL_TYPE_STDOUT, nil
+	case "stderr":
+		return observev0.LogChannelType_LOG_CHANNEL_TYPE_STDERR, nil
+	default:
+		return observev0.LogChannelType_LOG_CHANNEL_TYPE_UNSPECIFIED, fmt.Errorf("unknown channel %q, expected 'stdout' or 'stderr'", channel)
+	}
+}
+
+func toOutputLogItem(node string, item *observev0.LogItem) outputLogItem {
+	line := strings.TrimRight(item.GetLine(), "\r\n")
+	return outputLogItem{
+		Node:      node,
+		Channel:   item.GetChannel(),
+		Timestamp: time.Unix(item.GetTimestamp(), 0).UTC(),
+		Level:     logLevel(line),
+		Line:      line,
+	}
+}
 
-	return true
+// logLevel returns the severity found within the leading fields of a log
+// line as written by the auraed logger, e.g.
+// "2023-01-07T10:00:00.000000Z  INFO auraed: message". LogItem carries no
+// level of its own, so lines without a recognizable level yield "".
+func logLevel(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) > 3 {
+		fields = fields[:3]
+	}
+	for _, f := range fields {
+		switch l := strings.ToUpper(strings.Trim(f, "[]:")); l {
+		case "TRACE", "DEBUG", "INFO", "WARN", "ERROR":
+			return l
+		}
+	}
+	return ""
 }
 
 func NewCMD(ctx context.Context) *cobra.Command {
 	o := &option{
-		outputFormat: cli.NewOutputFormat().WithDefaultFormat(printer.NewJSON().Format()).WithPrinter(printer.NewJSON()),
+		channel: "stdout",
+		outputFormat: cli.NewOutputFormat().
+			WithDefaultFormat(printer.NewText().Format()).
+			WithPrinter(printer.NewText()).
+			WithPrinter(printer.NewJSON()).
+			WithPrinter(printer.NewNDJSON()),
 	}
 	cmd := &cobra.Command{
-		Use:   "observe <ip> <daemon|subprocesses>",
-		Short: "get a stream of logs either from the aurae daemon or spawned subprocesses running on the given IP address",
-		Args:  cobra.MinimumNArgs(2),
+		Use:   "observe <ip|ip,ip,...|cidr> <daemon|subprocesses|signals>",
+		Short: "get a stream of logs from the aurae daemon or spawned subprocesses, or of POSIX signals, on the given nodes",
+		Example: `ae observe 10.1.1.4 daemon
+ae observe 10.1.1.4 daemon --follow
+ae observe 10.1.1.4,10.1.1.5 daemon --sort-window 2s
+ae observe 10.1.1.0/24 subprocesses --pid 4242 --channel stderr
+ae observe 10.1.1.0/24 signals --cell web --signal KILL -o ndjson
+ae observe 10.1.1.0/24 daemon --follow --output-dir ./logs --max-size 50 --compress
+ae observe 10.1.1.0/24 daemon --follow --forward http://loki:3100
+ae observe 10.1.1.4 daemon --since 2026-10-01T14:02:00Z --until 2026-10-01T14:10:00Z
+ae observe 10.1.1.4 daemon --tail 100 --follow`,
+		Args: cobra.MinimumNArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// streams are closed cleanly on the first SIGINT or SIGTERM,
+			// a second one terminates the process
+			ctx, stop := ossignal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			go func() {
+				<-ctx.Done()
+				stop()
+			}()
 			return aeCMD.Run(ctx, o, cmd, args)
 		},
 	}
 	o.outputFormat.AddFlags(cmd)
+	cmd.Flags().Int64Var(&o.pid, "pid", o.pid, "The PID of the subprocess to observe, or to filter signals by")
+	cmd.Flags().StringVar(&o.channel, "channel", o.channel, "The subprocess log channel to observe. One of: (stdout, stderr).")
+	cmd.Flags().StringVar(&o.cell, "cell", o.cell, "Only observe signals of processes within the given cell. Subprocess logs can not be scoped to a cell, pass the --pid of the process instead")
+	cmd.Flags().StringVar(&o.signal, "signal", o.signal, "Only observe the given signal, by name or number")
+	cmd.Flags().BoolVarP(&o.follow, "follow", "f", o.follow, "Reconnect broken streams with an exponential backoff until interrupted")
+	cmd.Flags().StringVar(&o.since, "since", o.since, "Only print items logged after this timestamp (RFC 3339) or duration ago, e.g. 10m")
+	cmd.Flags().StringVar(&o.until, "until", o.until, "Only print items logged before this timestamp (RFC 3339) or duration ago and end each stream once it has passed and the stream is idle")
+	cmd.Flags().IntVar(&o.tail, "tail", -1, "Only print the last N items of every node logged before the command started, followed by new items")
+	cmd.Flags().DurationVar(&o.sortWindow, "sort-window", o.sortWindow, "Hold back log lines for the given duration to print them ordered by timestamp across nodes")
+	cmd.Flags().StringVar(&o.outputDir, "output-dir", o.outputDir, "Also write the items of every node and channel into rotated files below this directory")
+	cmd.Flags().Int64Var(&o.maxSize, "max-size", o.maxSize, "Rotate archived files once they reach this size in megabytes (0 disables)")
+	cmd.Flags().DurationVar(&o.maxAge, "max-age", o.maxAge, "Rotate archived files once they have been written to for this long (0 disables)")
+	cmd.Flags().BoolVar(&o.compress, "compress", o.compress, "Compress rotated archive files with gzip")
+	cmd.Flags().StringVar(&o.forward, "forward", o.forward, "Also push items to a Loki push API or OTLP/HTTP logs endpoint at this URL")
+	cmd.Flags().StringVar(&o.forwardFmt, "forward-format", o.forwardFmt, "Push format of --forward, either 'loki' or 'otlp' (detected from the URL by default)")
+	cmd.Flags().IntVar(&o.forwardBatch, "forward-batch-size", 500, "Maximum number of items pushed in one request")
+	cmd.Flags().IntVar(&o.forwardBuf, "forward-buffer", 10000, "Number of items buffered for --forward before new items are dropped")
+	cmd.Flags().IntVar(&o.parallel, "parallel", 64, "Maximum number of streams to open at the same time, established streams do not count")
 	cmd.Flags().Uint16Var(&o.port, "port", o.port, "The port to use when connecting")
 	cmd.Flags().BoolVar(&o.verbose, "verbose", o.verbose, "Lots of output")
 	return cmd
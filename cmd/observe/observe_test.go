@@ -1,16 +1,20 @@
 package observe
 
 import (
+	"bytes"
+	"reflect"
 	"testing"
 
 	"github.com/aurae-runtime/ae/pkg/cli"
 	"github.com/aurae-runtime/ae/pkg/cli/printer"
+
+	observev0 "github.com/aurae-runtime/ae/pkg/api/v0/observe"
 )
 
 func TestComplete(t *testing.T) {
 	ts := []struct {
 		args        []string
-		wantip      string
+		wanttarget  string
 		wantlogtype string
 		wanterr     bool
 	}{
@@ -41,8 +45,8 @@ func TestComplete(t *testing.T) {
 		if !tt.wanterr && goterr != nil {
 			t.Fatal("want no error, got error")
 		}
-		if tt.wantip != o.ip {
-			t.Fatalf("want ip %q, got ip %q", tt.wantip, o.ip)
+		if tt.wanttarget != o.target {
+			t.Fatalf("want target %q, got target %q", tt.wanttarget, o.target)
 		}
 		if tt.wantlogtype != o.logtype {
 			t.Fatalf("want logtype %q, got logtype %q", tt.wantlogtype, o.logtype)
@@ -54,8 +58,12 @@ func TestValidate(t *testing.T) {
 	ts := []struct {
 		name         string
 		outputFormat *cli.OutputFormat
-		ip           string
+		target       string
 		logtype      string
+		pid          int64
+		channel      string
+		cell         string
+		signal       string
 		wanterr      bool
 	}{
 		{
@@ -64,34 +72,139 @@ func TestValidate(t *testing.T) {
 			wanterr:      true,
 		},
 		{
-			name:         "no ip or logtype",
+			name:         "no target or logtype",
 			outputFormat: cli.NewOutputFormat().WithDefaultFormat("json").WithPrinter(printer.NewJSON()),
 			wanterr:      true,
 		},
 		{
 			name:         "invalid ip",
 			outputFormat: cli.NewOutputFormat().WithDefaultFormat("json").WithPrinter(printer.NewJSON()),
-			ip:           "invalid ip",
+			target:       "invalid ip",
 			wanterr:      true,
 		},
 		{
 			name:         "invalid logtype",
 			outputFormat: cli.NewOutputFormat().WithDefaultFormat("json").WithPrinter(printer.NewJSON()),
-			ip:           "10.0.0.0",
+			target:       "10.0.0.0",
 			logtype:      "invalid",
 			wanterr:      true,
 		},
+		{
+			name:         "invalid ip in list",
+			outputFormat: cli.NewOutputFormat().WithDefaultFormat("json").WithPrinter(printer.NewJSON()),
+			target:       "10.0.0.0,invalid ip",
+			logtype:      "daemon",
+			wanterr:      true,
+		},
+		{
+			name:         "invalid cidr",
+			outputFormat: cli.NewOutputFormat().WithDefaultFormat("json").WithPrinter(printer.NewJSON()),
+			target:       "10.0.0.0/99",
+			logtype:      "daemon",
+			wanterr:      true,
+		},
+		{
+			name:         "valid ip list and logtype",
+			outputFormat: cli.NewOutputFormat().WithDefaultFormat("json").WithPrinter(printer.NewJSON()),
+			target:       "10.0.0.0,10.0.0.1",
+			logtype:      "daemon",
+			wanterr:      false,
+		},
+		{
+			name:         "valid cidr and logtype",
+			outputFormat: cli.NewOutputFormat().WithDefaultFormat("json").WithPrinter(printer.NewJSON()),
+			target:       "10.0.0.0/30",
+			logtype:      "daemon",
+			wanterr:      false,
+		},
 		{
 			name:         "valid ip and logtype",
 			outputFormat: cli.NewOutputFormat().WithDefaultFormat("json").WithPrinter(printer.NewJSON()),
-			ip:           "10.0.0.0",
+			target:       "10.0.0.0",
 			logtype:      "daemon",
 			wanterr:      false,
 		},
+		{
+			name:         "daemon with pid",
+			outputFormat: cli.NewOutputFormat().WithDefaultFormat("json").WithPrinter(printer.NewJSON()),
+			target:       "10.0.0.0",
+			logtype:      "daemon",
+			pid:          42,
+			wanterr:      true,
+		},
+		{
+			name:         "subprocesses without pid",
+			outputFormat: cli.NewOutputFormat().WithDefaultFormat("json").WithPrinter(printer.NewJSON()),
+			target:       "10.0.0.0",
+			logtype:      "subprocesses",
+			channel:      "stdout",
+			wanterr:      true,
+		},
+		{
+			name:         "subprocesses with invalid channel",
+			outputFormat: cli.NewOutputFormat().WithDefaultFormat("json").WithPrinter(printer.NewJSON()),
+			target:       "10.0.0.0",
+			logtype:      "subprocesses",
+			pid:          42,
+			channel:      "stdin",
+			wanterr:      true,
+		},
+		{
+			name:         "daemon with signal filter",
+			outputFormat: cli.NewOutputFormat().WithDefaultFormat("json").WithPrinter(printer.NewJSON()),
+			target:       "10.0.0.0",
+			logtype:      "daemon",
+			signal:       "TERM",
+			wanterr:      true,
+		},
+		{
+			name:         "signals with invalid signal",
+			outputFormat: cli.NewOutputFormat().WithDefaultFormat("json").WithPrinter(printer.NewJSON()),
+			target:       "10.0.0.0",
+			logtype:      "signals",
+			signal:       "FOO",
+			wanterr:      true,
+		},
+		{
+			name:         "signals with filters",
+			outputFormat: cli.NewOutputFormat().WithDefaultFormat("json").WithPrinter(printer.NewJSON()),
+			target:       "10.0.0.0",
+			logtype:      "signals",
+			pid:          42,
+			cell:         "web",
+			signal:       "SIGKILL",
+			wanterr:      false,
+		},
+		{
+			name:         "daemon with cell",
+			outputFormat: cli.NewOutputFormat().WithDefaultFormat("json").WithPrinter(printer.NewJSON()),
+			target:       "10.0.0.0",
+			logtype:      "daemon",
+			cell:         "web",
+			wanterr:      true,
+		},
+		{
+			name:         "subprocesses with cell",
+			outputFormat: cli.NewOutputFormat().WithDefaultFormat("json").WithPrinter(printer.NewJSON()),
+			target:       "10.0.0.0",
+			logtype:      "subprocesses",
+			pid:          42,
+			cell:         "web",
+			wanterr:      true,
+		},
+		{
+			name:         "subprocesses with pid and channel",
+			outputFormat: cli.NewOutputFormat().WithDefaultFormat("json").WithPrinter(printer.NewJSON()),
+			target:       "10.0.0.0",
+			logtype:      "subprocesses",
+			pid:          42,
+			channel:      "stderr",
+			wanterr:      false,
+		},
 	}
 
 	for _, tt := range ts {
-		o := &option{ip: tt.ip, logtype: tt.logtype, outputFormat: tt.outputFormat}
+		o := &option{target: tt.target, logtype: tt.logtype, pid: tt.pid, channel: tt.channel, cell: tt.cell, signal: tt.signal, parallel: 64, outputFormat: tt.outputFormat}
 		goterr := o.Validate()
 		if tt.wanterr && goterr == nil {
 			t.Fatalf("[%s] want error, got no error", tt.name)
@@ -101,3 +214,87 @@ func TestValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestToOutputLogItem(t *testing.T) {
+	ts := []struct {
+		name      string
+		item      *observev0.LogItem
+		wantlevel string
+		wantline  string
+		wanttext  string
+		wantjson  string
+	}{
+		{
+			name:      "daemon line with level",
+			item:      &observev0.LogItem{Channel: "auraed", Line: "2023-01-07T10:00:00.000000Z  INFO auraed: started\n", Timestamp: 1673085600},
+			wantlevel: "INFO",
+			wantline:  "2023-01-07T10:00:00.000000Z  INFO auraed: started",
+			wanttext:  "10.0.0.1 2023-01-07T10:00:00Z 2023-01-07T10:00:00.000000Z  INFO auraed: started\n",
+			wantjson:  `{"node":"10.0.0.1","channel":"auraed","timestamp":"2023-01-07T10:00:00Z","level":"INFO","line":"2023-01-07T10:00:00.000000Z  INFO auraed: started"}` + "\n",
+		},
+		{
+			name:      "subprocess line without level",
+			item:      &observev0.LogItem{Channel: "stdout", Line: "hello world", Timestamp: 1673085600},
+			wantlevel: "",
+			wantline:  "hello world",
+			wanttext:  "10.0.0.1 2023-01-07T10:00:00Z hello world\n",
+			wantjson:  `{"node":"10.0.0.1","channel":"stdout","timestamp":"2023-01-07T10:00:00Z","line":"hello world"}` + "\n",
+		},
+	}
+
+	for _, tt := range ts {
+		got := toOutputLogItem("10.0.0.1", tt.item)
+		if got.Level != tt.wantlevel {
+			t.Fatalf("[%s] want level %q, got level %q", tt.name, tt.wantlevel, got.Level)
+		}
+		if got.Line != tt.wantline {
+			t.Fatalf("[%s] want line %q, got line %q", tt.name, tt.wantline, got.Line)
+		}
+
+		text := &bytes.Buffer{}
+		if err := printer.NewText().Print(text, got); err != nil {
+			t.Fatalf("[%s] want no error, got error: %s", tt.name, err)
+		}
+		if text.String() != tt.wanttext {
+			t.Fatalf("[%s] want text %q, got text %q", tt.name, tt.wanttext, text.String())
+		}
+
+		json := &bytes.Buffer{}
+		if err := printer.NewNDJSON().Print(json, got); err != nil {
+			t.Fatalf("[%s] want no error, got error: %s", tt.name, err)
+		}
+		if json.String() != tt.wantjson {
+			t.Fatalf("[%s] want json %q, got json %q", tt.name, tt.wantjson, json.String())
+		}
+	}
+}
+
+func TestTargetIPs(t *testing.T) {
+	ts := []struct {
+		target  string
+		wantips []string
+		wanterr bool
+	}{
+		{"10.0.0.1", []string{"10.0.0.1"}, false},
+		{"10.0.0.1, 10.0.0.2", []string{"10.0.0.1", "10.0.0.2"}, false},
+		{"10.0.0.0/30", []string{"10.0.0.0", "10.0.0.1", "10.0.0.2", "10.0.0.3"}, false},
+		{"::1", []string{"::1"}, false},
+		{"10.0.0.1,", nil, true},
+		{"foo", nil, true},
+		{"10.0.0.0/16", nil, true},
+		{"fd00::/64", nil, true},
+	}
+
+	for _, tt := range ts {
+		got, err := targetIPs(tt.target)
+		if tt.wanterr && err == nil {
+			t.Fatalf("[%s] want error, got no error", tt.target)
+		}
+		if !tt.wanterr && err != nil {
+			t.Fatalf("[%s] want no error, got error: %s", tt.target, err)
+		}
+		if !reflect.DeepEqual(got, tt.wantips) {
+			t.Fatalf("[%s] want ips %v, got ips %v", tt.target, tt.wantips, got)
+		}
+	}
+}
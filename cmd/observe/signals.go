@@ -0,0 +1,136 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package observe
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aurae-runtime/ae/pkg/observe"
+	"github.com/aurae-runtime/ae/pkg/signal"
+
+	observev0 "github.com/aurae-runtime/ae/pkg/api/v0/observe"
+)
+
+type outputSignal struct {
+	Node      string    `json:"node" yaml:"node"`
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+	Signal    int32     `json:"signal" yaml:"signal"`
+	Name      string    `json:"name" yaml:"name"`
+	ProcessID int64     `json:"pid" yaml:"pid"`
+	Cell      string    `json:"cell,omitempty" yaml:"cell,omitempty"`
+}
+
+func (s outputSignal) String() string {
+	out := fmt.Sprintf("%s %s %s(%d) pid=%d", s.Node, s.Timestamp.Format(time.RFC3339), s.Name, s.Signal, s.ProcessID)
+	if s.Cell != "" {
+		out += fmt.Sprintf(" cell=%s", s.Cell)
+	}
+	return out
+}
+
+func (s outputSignal) at() time.Time {
+	return s.Timestamp
+}
+
+func (s outputSignal) source() (string, string) {
+	return s.Node, "signals"
+}
+
+// streamSignals forwards the signals delivered to processes on a node. The
+// cell filter is applied by auraed, signal and PID filters are applied here.
+// Signals carry no timestamp, so the time of receipt is used and checked
+// against --since and --until.
+func (o *option) streamSignals(ip_str string, obs observe.Observe, items chan<- outputItem, opened func()) (bool, error) {
+	req := observev0.GetPosixSignalsStreamRequest{}
+	if o.cell != "" {
+		req.Workload = &observev0.WorkloadReference{
+			WorkloadType: observev0.WorkloadType_WORKLOAD_TYPE_CELL,
+			Id:           o.cell,
+		}
+	}
+
+	var sig int32
+	if o.signal != "" {
+		var err error
+		if sig, err = signal.Parse(o.signal); err != nil {
+			return false, err
+		}
+	}
+
+	ctx, touch, cancel := o.streamContext(0)
+	defer cancel()
+
+	stream, err := obs.GetPosixSignalsStream(ctx, &req)
+	if err != nil {
+		return false, err
+	}
+	opened()
+
+	delivered := false
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return delivered, nil
+		}
+		if err != nil {
+			return delivered, o.untilErr(ctx, err)
+		}
+		touch()
+		s := resp.GetSignal()
+		if sig != 0 && s.GetSignal() != sig {
+			continue
+		}
+		if o.pid != 0 && s.GetProcessId() != o.pid {
+			continue
+		}
+		now := time.Now().UTC()
+		if !o.untilTime.IsZero() && now.After(o.untilTime) {
+			return delivered, errUntilPassed
+		}
+		if now.Before(o.sinceTime) {
+			continue
+		}
+		out := outputSignal{
+			Node:      ip_str,
+			Timestamp: now,
+			Signal:    s.GetSignal(),
+			Name:      signal.Name(s.GetSignal()),
+			ProcessID: s.GetProcessId(),
+			Cell:      o.cell,
+		}
+		if !o.send(items, out) {
+			return delivered, nil
+		}
+		delivered = true
+	}
+}
@@ -0,0 +1,40 @@
+package observe
+
+import (
+	"context"
+	"testing"
+
+	observev0 "github.com/aurae-runtime/ae/pkg/api/v0/observe"
+)
+
+func TestStreamSignals(t *testing.T) {
+	obs := &fakeObserve{signals: &fakeSignalsStream{signals: []*observev0.Signal{
+		{Signal: 15, ProcessId: 100},
+		{Signal: 9, ProcessId: 100},
+		{Signal: 9, ProcessId: 200},
+	}}}
+
+	o := &option{ctx: context.Background(), logtype: "signals", cell: "web", signal: "KILL", pid: 100}
+	items := make(chan outputItem, 10)
+
+	delivered, err := o.streamHost("10.0.0.1", obs, &resumeTracker{}, items, func() {})
+	if !delivered || err != nil {
+		t.Fatalf("want delivered items and no error, got %t and %v", delivered, err)
+	}
+	close(items)
+
+	if w := obs.signalsReq.GetWorkload(); w.GetId() != "web" || w.GetWorkloadType() != observev0.WorkloadType_WORKLOAD_TYPE_CELL {
+		t.Fatalf("want request for cell %q, got %v", "web", w)
+	}
+
+	var got []outputSignal
+	for item := range items {
+		got = append(got, item.(outputSignal))
+	}
+	if len(got) != 1 {
+		t.Fatalf("want 1 signal, got %d", len(got))
+	}
+	if got[0].Name != "SIGKILL" || got[0].ProcessID != 100 || got[0].Cell != "web" || got[0].Node != "10.0.0.1" {
+		t.Fatalf("unexpected signal %+v", got[0])
+	}
+}
@@ -0,0 +1,97 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package observe
+
+import (
+	"container/heap"
+	"time"
+)
+
+// sortBuffer reorders items of several nodes by their timestamp. Every
+// item is held back until it has been buffered for the length of the window,
+// giving items of slower nodes the chance to be sorted in before it.
+type sortBuffer struct {
+	window time.Duration
+	items  sortItems
+	seq    uint64
+}
+
+type sortItem struct {
+	item    outputItem
+	arrival time.Time
+	seq     uint64
+}
+
+func newSortBuffer(window time.Duration) *sortBuffer {
+	return &sortBuffer{window: window}
+}
+
+func (b *sortBuffer) Push(item outputItem, now time.Time) {
+	b.seq++
+	heap.Push(&b.items, sortItem{item: item, arrival: now, seq: b.seq})
+}
+
+// Release removes and returns, in timestamp order, the buffered items which
+// arrived at least one window before now.
+func (b *sortBuffer) Release(now time.Time) []outputItem {
+	var out []outputItem
+	for b.items.Len() > 0 && !b.items[0].arrival.Add(b.window).After(now) {
+		out = append(out, heap.Pop(&b.items).(sortItem).item)
+	}
+	return out
+}
+
+func (b *sortBuffer) Len() int {
+	return b.items.Len()
+}
+
+type sortItems []sortItem
+
+func (s sortItems) Len() int { return len(s) }
+
+func (s sortItems) Less(i, j int) bool {
+	if ti, tj := s[i].item.at(), s[j].item.at(); !ti.Equal(tj) {
+		return ti.Before(tj)
+	}
+	return s[i].seq < s[j].seq
+}
+
+func (s sortItems) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+func (s *sortItems) Push(x any) { *s = append(*s, x.(sortItem)) }
+
+func (s *sortItems) Pop() any {
+	old := *s
+	n := len(old)
+	item := old[n-1]
+	*s = old[:n-1]
+	return item
+}
@@ -0,0 +1,35 @@
+package observe
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSortBuffer(t *testing.T) {
+	start := time.Unix(1673085600, 0)
+	window := 2 * time.Second
+	b := newSortBuffer(window)
+
+	// node b's line arrives late, but carries the earliest timestamp
+	b.Push(outputLogItem{Node: "a", Timestamp: start.Add(time.Second), Line: "a1"}, start)
+	b.Push(outputLogItem{Node: "a", Timestamp: start.Add(time.Second), Line: "a2"}, start)
+	b.Push(outputLogItem{Node: "b", Timestamp: start, Line: "b1"}, start.Add(time.Second))
+
+	if got := b.Release(start.Add(time.Second)); len(got) != 0 {
+		t.Fatalf("want no items before the window passed, got %v", got)
+	}
+
+	got := b.Release(start.Add(3 * time.Second))
+	want := []string{"b1", "a1", "a2"}
+	if len(got) != len(want) {
+		t.Fatalf("want %d items, got %d", len(want), len(got))
+	}
+	for i, item := range got {
+		if line := item.(outputLogItem).Line; line != want[i] {
+			t.Fatalf("want item %d to be %q, got %q", i, want[i], line)
+		}
+	}
+	if b.Len() != 0 {
+		t.Fatalf("want empty buffer, got %d items", b.Len())
+	}
+}
@@ -0,0 +1,183 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package observe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// untilGrace is how long a stream may stay silent once --until has passed
+// before it is ended. auraed keeps streams open after replaying the items
+// logged before --until, so the end of the replay is only noticed by the
+// stream going quiet.
+var untilGrace = 5 * time.Second
+
+// tailIdle is how long the backlog of a node is held back for --tail after
+// its last replayed item before it is printed, so quiet nodes print their
+// backlog without waiting for a live item.
+const tailIdle = time.Second
+
+// errUntilPassed ends the stream of a node once it delivers an item newer
+// than --until.
+var errUntilPassed = errors.New("--until has passed")
+
+// streamContext returns the context a single stream is opened with. Once
+// --until has passed, the context is canceled as soon as the stream has not
+// delivered an item for grace. touch is called for every item received.
+func (o *option) streamContext(grace time.Duration) (ctx context.Context, touch func(), cancel context.CancelFunc) {
+	if o.untilTime.IsZero() {
+		return o.ctx, func() {}, func() {}
+	}
+
+	wait := func() time.Duration {
+		d := time.Until(o.untilTime)
+		if d < 0 {
+			d = 0
+		}
+		return d + grace
+	}
+
+	ctx, cancelCtx := context.WithCancel(o.ctx)
+	timer := time.AfterFunc(wait(), cancelCtx)
+	touch = func() { timer.Reset(wait()) }
+	cancel = func() {
+		timer.Stop()
+		cancelCtx()
+	}
+	return ctx, touch, cancel
+}
+
+// untilErr turns the error of a stream ended by streamContext into
+// errUntilPassed.
+func (o *option) untilErr(ctx context.Context, err error) error {
+	if ctx.Err() != nil && o.ctx.Err() == nil {
+		return errUntilPassed
+	}
+	return err
+}
+
+// parseTimeFlag parses the value of --since and --until, either as an
+// RFC 3339 timestamp or as a duration relative to now.
+func parseTimeFlag(flag, value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("invalid --%s %q, expected a duration such as 10m or a timestamp such as 2006-01-02T15:04:05Z", flag, value)
+	}
+	return now.Add(-d), nil
+}
+
+// tailBuffer holds back the items each node delivered from before the start
+// of the command and only passes on the last n of them, once the first live
+// item of the node arrives, the replay has been idle for tailIdle or the
+// stream ends.
+type tailBuffer struct {
+	n     int
+	start time.Time
+	now   func() time.Time
+	held  map[string][]outputItem
+	last  map[string]time.Time
+	live  map[string]bool
+}
+
+func newTailBuffer(n int, start time.Time) *tailBuffer {
+	return &tailBuffer{
+		n:     n,
+		start: start.Truncate(time.Second),
+		now:   time.Now,
+		held:  make(map[string][]outputItem),
+		last:  make(map[string]time.Time),
+		live:  make(map[string]bool),
+	}
+}
+
+// Add returns the items to deliver after item has been observed.
+func (t *tailBuffer) Add(item outputItem) []outputItem {
+	node, _ := item.source()
+	if t.live[node] {
+		return []outputItem{item}
+	}
+	if item.at().Before(t.start) {
+		held := append(t.held[node], item)
+		if len(held) > t.n {
+			held = held[len(held)-t.n:]
+		}
+		t.held[node] = held
+		t.last[node] = t.now()
+		return nil
+	}
+	t.live[node] = true
+	out := append(t.held[node], item)
+	delete(t.held, node)
+	return out
+}
+
+// Release returns the items held back for nodes that have not replayed an
+// item for tailIdle, ordered by node. Their following items are passed on
+// right away.
+func (t *tailBuffer) Release(now time.Time) []outputItem {
+	return t.release(func(node string) bool {
+		return now.Sub(t.last[node]) >= tailIdle
+	})
+}
+
+// Flush returns all items still held back, ordered by node.
+func (t *tailBuffer) Flush() []outputItem {
+	return t.release(func(string) bool { return true })
+}
+
+func (t *tailBuffer) release(done func(node string) bool) []outputItem {
+	nodes := make([]string, 0, len(t.held))
+	for node := range t.held {
+		if done(node) {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Strings(nodes)
+
+	var out []outputItem
+	for _, node := range nodes {
+		out = append(out, t.held[node]...)
+		delete(t.held, node)
+		delete(t.last, node)
+		t.live[node] = true
+	}
+	return out
+}
@@ -0,0 +1,164 @@
+package observe
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	observev0 "github.com/aurae-runtime/ae/pkg/api/v0/observe"
+)
+
+func TestParseTimeFlag(t *testing.T) {
+	now := time.Date(2026, 10, 1, 14, 30, 0, 0, time.UTC)
+	ts := []struct {
+		value   string
+		want    time.Time
+		wanterr bool
+	}{
+		{value: "", want: time.Time{}},
+		{value: "10m", want: now.Add(-10 * time.Minute)},
+		{value: "2026-10-01T14:02:00Z", want: time.Date(2026, 10, 1, 14, 2, 0, 0, time.UTC)},
+		{value: "-5m", wanterr: true},
+		{value: "yesterday", wanterr: true},
+	}
+	for _, tt := range ts {
+		got, err := parseTimeFlag("since", tt.value, now)
+		if tt.wanterr != (err != nil) {
+			t.Fatalf("[%s] want error %t, got %v", tt.value, tt.wanterr, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Fatalf("[%s] want %s, got %s", tt.value, tt.want, got)
+		}
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	start := time.Unix(1673085600, 0)
+	b := newTailBuffer(2, start)
+
+	for i, line := range []string{"a1", "a2", "a3"} {
+		item := outputLogItem{Node: "a", Timestamp: start.Add(time.Duration(i-3) * time.Second), Line: line}
+		if got := b.Add(item); len(got) != 0 {
+			t.Fatalf("want backlog to be held back, got %v", got)
+		}
+	}
+	b.Add(outputLogItem{Node: "b", Timestamp: start.Add(-time.Second), Line: "b1"})
+
+	got := b.Add(outputLogItem{Node: "a", Timestamp: start, Line: "a4"})
+	assertLines(t, got, "a2", "a3", "a4")
+	assertLines(t, b.Add(outputLogItem{Node: "a", Timestamp: start.Add(-time.Minute), Line: "a5"}), "a5")
+	assertLines(t, b.Flush(), "b1")
+}
+
+func TestTailBufferRelease(t *testing.T) {
+	start := time.Unix(1673085600, 0)
+	now := start
+	b := newTailBuffer(1, start)
+	b.now = func() time.Time { return now }
+
+	b.Add(outputLogItem{Node: "a", Timestamp: start.Add(-2 * time.Second), Line: "a1"})
+	now = now.Add(tailIdle / 2)
+	b.Add(outputLogItem{Node: "b", Timestamp: start.Add(-time.Second), Line: "b1"})
+
+	assertLines(t, b.Release(now))
+	assertLines(t, b.Release(start.Add(tailIdle)), "a1")
+	assertLines(t, b.Add(outputLogItem{Node: "a", Timestamp: start.Add(-time.Second), Line: "a2"}), "a2")
+	assertLines(t, b.Release(now.Add(tailIdle)), "b1")
+	assertLines(t, b.Flush())
+}
+
+func TestStreamHostWindow(t *testing.T) {
+	obs := &fakeObserve{streams: []*fakeDaemonLogStream{{
+		items: []*observev0.LogItem{
+			{Line: "before", Timestamp: 10},
+			{Line: "within", Timestamp: 20},
+			{Line: "after", Timestamp: 40},
+			{Line: "never", Timestamp: 20},
+		},
+		err: io.EOF,
+	}}}
+
+	o := &option{ctx: context.Background(), logtype: "daemon", sinceTime: time.Unix(15, 0), untilTime: time.Unix(30, 0)}
+	items := make(chan outputItem, 10)
+
+	_, err := o.streamHost("10.0.0.1", obs, &resumeTracker{}, items, func() {})
+	if !errors.Is(err, errUntilPassed) {
+		t.Fatalf("want stream to end at --until, got %v", err)
+	}
+	close(items)
+
+	var got []outputItem
+	for item := range items {
+		got = append(got, item)
+	}
+	assertLines(t, got, "within")
+}
+
+func TestStreamHostUntilIdle(t *testing.T) {
+	defer func(grace time.Duration) { untilGrace = grace }(untilGrace)
+	untilGrace = 10 * time.Millisecond
+
+	// the stream stays open after the replay, it is ended once it has been
+	// quiet for untilGrace
+	obs := &fakeObserve{streams: []*fakeDaemonLogStream{{
+		items: []*observev0.LogItem{{Line: "within", Timestamp: 20}},
+	}}}
+
+	o := &option{ctx: context.Background(), logtype: "daemon", untilTime: time.Unix(30, 0)}
+	items := make(chan outputItem, 10)
+
+	_, err := o.streamHost("10.0.0.1", obs, &resumeTracker{}, items, func() {})
+	if !errors.Is(err, errUntilPassed) {
+		t.Fatalf("want stream to end once idle after --until, got %v", err)
+	}
+	close(items)
+
+	var got []outputItem
+	for item := range items {
+		got = append(got, item)
+	}
+	assertLines(t, got, "within")
+}
+
+func TestStreamSignalsWindow(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		since     time.Time
+		until     time.Time
+		wantErr   error
+		wantItems int
+	}{
+		{name: "within", since: now.Add(-time.Minute), until: now.Add(time.Minute), wantItems: 1},
+		{name: "before since", since: now.Add(time.Minute)},
+		{name: "after until", until: now.Add(-time.Minute), wantErr: errUntilPassed},
+	}
+
+	for _, tt := range tests {
+		obs := &fakeObserve{signals: &fakeSignalsStream{signals: []*observev0.Signal{{Signal: 15, ProcessId: 42}}}}
+		o := &option{ctx: context.Background(), logtype: "signals", sinceTime: tt.since, untilTime: tt.until}
+		items := make(chan outputItem, 10)
+
+		_, err := o.streamHost("10.0.0.1", obs, &resumeTracker{}, items, func() {})
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("[%s] want error %v, got %v", tt.name, tt.wantErr, err)
+		}
+		if len(items) != tt.wantItems {
+			t.Fatalf("[%s] want %d signals, got %d", tt.name, tt.wantItems, len(items))
+		}
+	}
+}
+
+func assertLines(t *testing.T, items []outputItem, want ...string) {
+	t.Helper()
+	if len(items) != len(want) {
+		t.Fatalf("want lines %v, got %v", want, items)
+	}
+	for i, item := range items {
+		if line := item.(outputLogItem).Line; line != want[i] {
+			t.Fatalf("want lines %v, got %v", want, items)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package bundle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+
+	aeCMD "github.com/aurae-runtime/ae/cmd"
+	"github.com/aurae-runtime/ae/pkg/oci"
+	"github.com/spf13/cobra"
+)
+
+type option struct {
+	aeCMD.Option
+	from   string
+	out    string
+	writer io.Writer
+}
+
+func (o *option) Complete(_ []string) error {
+	return nil
+}
+
+func (o *option) Validate() error {
+	if o.from == "" {
+		return errors.New("--from-oci-layout must be passed to this command")
+	}
+	if dir, _ := oci.ParseLayoutRef(o.from); dir == "" {
+		return errors.New("--from-oci-layout must name an image layout directory")
+	}
+	if o.out == "" {
+		return errors.New("--out must be passed to this command")
+	}
+	return nil
+}
+
+func (o *option) Execute(_ context.Context) error {
+	dir, tag := oci.ParseLayoutRef(o.from)
+	warnings, err := oci.CreateBundle(dir, tag, o.out)
+	for _, w := range warnings {
+		log.Printf("warning: %s\n", w)
+	}
+	return err
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func NewCMD(ctx context.Context) *cobra.Command {
+	o := &option{}
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Unpack an image from an OCI image layout into a runtime bundle.",
+		Long: `Unpack an image from an OCI image layout into a runtime bundle.
+
+The layers of the image are applied into rootfs/ of the bundle, handling
+whiteouts, and a default config.json is generated from the image
+configuration. Everything is read from disk, no registry is contacted.`,
+		Example: `skopeo copy docker://nginx:latest oci:nginx:latest
+ae oci bundle --from-oci-layout nginx:latest --out web
+ae oci create web --bundle web`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return oci.LogError(aeCMD.Run(ctx, o, cmd, args))
+		},
+	}
+	cmd.Flags().StringVar(&o.from, "from-oci-layout", o.from, "The image to unpack as <dir>:<tag>, the tag may be omitted for layouts holding a single image")
+	cmd.Flags().StringVar(&o.out, "out", o.out, "The bundle directory to create, must not exist or be empty")
+
+	return cmd
+}
@@ -32,35 +32,70 @@ package create
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 
 	aeCMD "github.com/aurae-runtime/ae/cmd"
-	"github.com/aurae-runtime/ae/pkg/cli"
-	"github.com/aurae-runtime/ae/pkg/cli/printer"
+	"github.com/aurae-runtime/ae/pkg/client"
+	"github.com/aurae-runtime/ae/pkg/config"
+	"github.com/aurae-runtime/ae/pkg/oci"
 	"github.com/spf13/cobra"
 )
 
 type option struct {
 	aeCMD.Option
-	writer       io.Writer
-	outputFormat *cli.OutputFormat
+	id            string
+	bundle        string
+	pidFile       string
+	consoleSocket string
+	noPivot       bool
+	noNewKeyring  bool
+	preserveFds   int
+	pidfdSocket   string
+	auth          *config.Auth
+	globals       *oci.Globals
+	writer        io.Writer
 }
 
-func (o *option) Complete(_ []string) error {
+func (o *option) Complete(args []string) error {
+	if len(args) != 1 {
+		return errors.New("expected container id to be passed to this command")
+	}
+	o.id = args[0]
 	return nil
 }
 
 func (o *option) Validate() error {
-	if err := o.outputFormat.Validate(); err != nil {
+	if err := oci.ValidateID(o.id); err != nil {
 		return err
 	}
+	if o.bundle == "" {
+		return errors.New("--bundle must not be empty")
+	}
 	return nil
 }
 
-func (o *option) Execute(_ context.Context) error {
-	fmt.Fprintln(o.writer, "create called")
-	return nil
+func (o *option) Execute(ctx context.Context) error {
+	c, err := client.New(ctx, config.WithAuth(*o.auth))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	cells, err := c.Cells()
+	if err != nil {
+		return err
+	}
+
+	if o.consoleSocket != "" {
+		log.Printf("warning: --console-socket is not supported, the container process has no terminal\n")
+	}
+
+	warnings, err := oci.NewRuntime(o.globals.Store(), cells).Create(ctx, o.id, o.bundle, oci.CreateOptions{PidFile: o.pidFile})
+	for _, w := range warnings {
+		log.Printf("warning: %s\n", w)
+	}
+	return err
 }
 
 func (o *option) SetWriter(writer io.Writer) {
@@ -69,19 +104,35 @@ func (o *option) SetWriter(writer io.Writer) {
 
 func NewCMD(ctx context.Context) *cobra.Command {
 	o := &option{
-		outputFormat: cli.NewOutputFormat().
-			WithPrinter(printer.NewJSON()).
-			WithPrinter(printer.NewYAML()),
+		bundle: ".",
+		auth:   &config.Auth{},
 	}
 	cmd := &cobra.Command{
-		Use:   "create",
+		Use:   "create <container-id>",
 		Short: "Create a container from a bundle directory.",
-		Long:  `Create a container from a bundle directory.`,
+		Long: `Create a container from a bundle directory.
+
+The config.json of the bundle is validated and its cgroup and namespace
+settings are used to allocate a cell named after the container. The container
+process is started within the cell by 'ae oci start', which also writes the
+--pid-file. --console-socket, --no-pivot, --no-new-keyring and --preserve-fds
+are accepted for runc compatibility and ignored.`,
+		Example: `ae oci create web --bundle /var/lib/bundles/web`,
+		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return aeCMD.Run(ctx, o, cmd, args)
+			o.globals = oci.GlobalsFrom(cmd)
+			return oci.LogError(aeCMD.Run(ctx, o, cmd, args))
 		},
 	}
-	o.outputFormat.AddFlags(cmd)
+	o.auth.AddFlags(cmd)
+	cmd.Flags().StringVarP(&o.bundle, "bundle", "b", o.bundle, "Path to the root of the bundle directory")
+	cmd.Flags().StringVar(&o.pidFile, "pid-file", o.pidFile, "File to write the PID of the container process to once it has been started")
+	cmd.Flags().StringVar(&o.consoleSocket, "console-socket", o.consoleSocket, "Accepted for runc compatibility and ignored")
+	cmd.Flags().BoolVar(&o.noPivot, "no-pivot", o.noPivot, "Accepted for runc compatibility and ignored")
+	cmd.Flags().BoolVar(&o.noNewKeyring, "no-new-keyring", o.noNewKeyring, "Accepted for runc compatibility and ignored")
+	cmd.Flags().IntVar(&o.preserveFds, "preserve-fds", o.preserveFds, "Accepted for runc compatibility and ignored")
+	cmd.Flags().StringVar(&o.pidfdSocket, "pidfd-socket", o.pidfdSocket, "Accepted for runc compatibility and ignored")
+	_ = cmd.Flags().MarkHidden("pidfd-socket")
 
 	return cmd
 }
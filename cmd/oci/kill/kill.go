@@ -32,35 +32,65 @@ package kill
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 
 	aeCMD "github.com/aurae-runtime/ae/cmd"
-	"github.com/aurae-runtime/ae/pkg/cli"
-	"github.com/aurae-runtime/ae/pkg/cli/printer"
+	"github.com/aurae-runtime/ae/pkg/client"
+	"github.com/aurae-runtime/ae/pkg/config"
+	"github.com/aurae-runtime/ae/pkg/oci"
+	"github.com/aurae-runtime/ae/pkg/signal"
 	"github.com/spf13/cobra"
 )
 
 type option struct {
 	aeCMD.Option
-	writer       io.Writer
-	outputFormat *cli.OutputFormat
+	id      string
+	signal  string
+	all     bool
+	auth    *config.Auth
+	globals *oci.Globals
+	writer  io.Writer
 }
 
-func (o *option) Complete(_ []string) error {
+func (o *option) Complete(args []string) error {
+	if len(args) != 1 && len(args) != 2 {
+		return errors.New("expected container id and optionally a signal to be passed to this command")
+	}
+	o.id = args[0]
+	o.signal = "SIGTERM"
+	if len(args) == 2 {
+		o.signal = args[1]
+	}
 	return nil
 }
 
 func (o *option) Validate() error {
-	if err := o.outputFormat.Validate(); err != nil {
+	if err := oci.ValidateID(o.id); err != nil {
+		return err
+	}
+	if _, err := signal.Parse(o.signal); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (o *option) Execute(_ context.Context) error {
-	fmt.Fprintln(o.writer, "kill called")
-	return nil
+func (o *option) Execute(ctx context.Context) error {
+	sig, err := signal.Parse(o.signal)
+	if err != nil {
+		return err
+	}
+
+	c, err := client.New(ctx, config.WithAuth(*o.auth))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	cells, err := c.Cells()
+	if err != nil {
+		return err
+	}
+	return oci.NewRuntime(o.globals.Store(), cells).Kill(ctx, o.id, sig)
 }
 
 func (o *option) SetWriter(writer io.Writer) {
@@ -69,19 +99,27 @@ func (o *option) SetWriter(writer io.Writer) {
 
 func NewCMD(ctx context.Context) *cobra.Command {
 	o := &option{
-		outputFormat: cli.NewOutputFormat().
-			WithPrinter(printer.NewJSON()).
-			WithPrinter(printer.NewYAML()),
+		auth: &config.Auth{},
 	}
 	cmd := &cobra.Command{
-		Use:   "kill",
+		Use:   "kill <container-id> [signal]",
 		Short: "Send a signal to the container process.",
-		Long:  `Send a signal to the container process.`,
+		Long: `Send a signal to the container process.
+
+The signal is given by name (TERM, SIGKILL) or number and defaults to
+SIGTERM. Auraed stops container processes as a whole, so only the signals
+KILL and TERM are supported. Only created and running containers can be
+killed.`,
+		Example: `ae oci kill web
+ae oci kill web KILL`,
+		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return aeCMD.Run(ctx, o, cmd, args)
+			o.globals = oci.GlobalsFrom(cmd)
+			return oci.LogError(aeCMD.Run(ctx, o, cmd, args))
 		},
 	}
-	o.outputFormat.AddFlags(cmd)
+	o.auth.AddFlags(cmd)
+	cmd.Flags().BoolVarP(&o.all, "all", "a", o.all, "Accepted for runc compatibility, all processes of the container are stopped anyway")
 
 	return cmd
 }
@@ -0,0 +1,58 @@
+package kill
+
+import (
+	"testing"
+)
+
+func TestComplete(t *testing.T) {
+	ts := []struct {
+		args       []string
+		wantid     string
+		wantsignal string
+		wanterr    bool
+	}{
+		{args: []string{}, wanterr: true},
+		{args: []string{"web"}, wantid: "web", wantsignal: "SIGTERM"},
+		{args: []string{"web", "KILL"}, wantid: "web", wantsignal: "KILL"},
+		{args: []string{"web", "KILL", "now"}, wanterr: true},
+	}
+
+	for _, tt := range ts {
+		o := &option{}
+		goterr := o.Complete(tt.args)
+		if tt.wanterr && goterr == nil {
+			t.Fatal("want error, got no error")
+		}
+		if !tt.wanterr && goterr != nil {
+			t.Fatalf("want no error, got error %q", goterr)
+		}
+		if o.id != tt.wantid || (!tt.wanterr && o.signal != tt.wantsignal) {
+			t.Fatalf("want id %q and signal %q, got id %q and signal %q", tt.wantid, tt.wantsignal, o.id, o.signal)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	ts := []struct {
+		name    string
+		id      string
+		signal  string
+		wanterr bool
+	}{
+		{name: "valid", id: "web", signal: "TERM"},
+		{name: "number", id: "web", signal: "9"},
+		{name: "invalid id", id: "../web", signal: "TERM", wanterr: true},
+		{name: "invalid signal", id: "web", signal: "FOO", wanterr: true},
+	}
+
+	for _, tt := range ts {
+		o := &option{id: tt.id, signal: tt.signal}
+		goterr := o.Validate()
+		if tt.wanterr && goterr == nil {
+			t.Fatalf("[%s] want error, got no error", tt.name)
+		}
+		if !tt.wanterr && goterr != nil {
+			t.Fatalf("[%s] want no error, got error: %s", tt.name, goterr)
+		}
+	}
+}
@@ -36,17 +36,20 @@ import (
 	"io"
 
 	aeCMD "github.com/aurae-runtime/ae/cmd"
+	"github.com/aurae-runtime/ae/cmd/oci/bundle"
 	"github.com/aurae-runtime/ae/cmd/oci/create"
 	"github.com/aurae-runtime/ae/cmd/oci/delete"
 	"github.com/aurae-runtime/ae/cmd/oci/kill"
 	"github.com/aurae-runtime/ae/cmd/oci/start"
 	"github.com/aurae-runtime/ae/cmd/oci/state"
+	"github.com/aurae-runtime/ae/pkg/oci"
 	"github.com/spf13/cobra"
 )
 
 type option struct {
 	aeCMD.Option
-	writer io.Writer
+	globals *oci.Globals
+	writer  io.Writer
 }
 
 func (o *option) Complete(_ []string) error {
@@ -67,21 +70,47 @@ func (o *option) SetWriter(writer io.Writer) {
 }
 
 func NewCMD(ctx context.Context) *cobra.Command {
-	o := &option{}
+	o := &option{
+		globals: &oci.Globals{},
+	}
 	cmd := &cobra.Command{
 		Use:   "oci",
 		Short: "OCI Runtime Command Line Interface.",
-		Long:  `OCI Runtime Command Line Interface.`,
+		Long: `OCI Runtime Command Line Interface.
+
+The global flags of runc are accepted, so ae can be used as the runtime binary
+of container managers such as containerd. Invoked through a link named
+'ae-oci', ae behaves as 'ae oci'. Flags unknown to ae are ignored, their
+values must be given as --flag=value to not be taken for arguments.`,
+		Example: `ae oci --root /run/ae --log /var/log/ae.json --log-format json create web --bundle . --pid-file web.pid`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// errors are reported through the log, not followed by the usage
+			cmd.SilenceUsage = true
+			if err := o.globals.Validate(); err != nil {
+				return err
+			}
+			return o.globals.SetupLog()
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return aeCMD.Run(ctx, o, cmd, args)
 		},
 	}
+	o.globals.AddFlags(cmd)
 
+	cmd.AddCommand(bundle.NewCMD(ctx))
 	cmd.AddCommand(create.NewCMD(ctx))
 	cmd.AddCommand(delete.NewCMD(ctx))
 	cmd.AddCommand(kill.NewCMD(ctx))
 	cmd.AddCommand(start.NewCMD(ctx))
 	cmd.AddCommand(state.NewCMD(ctx))
 
+	// flags of newer runc versions are ignored like the unsupported ones
+	// known to ae. Unknown flags take the following argument as their value,
+	// so the runc flags are declared with their types instead.
+	cmd.FParseErrWhitelist.UnknownFlags = true
+	for _, sub := range cmd.Commands() {
+		sub.FParseErrWhitelist.UnknownFlags = true
+	}
+
 	return cmd
 }
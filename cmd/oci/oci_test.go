@@ -0,0 +1,35 @@
+package oci
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestUnknownFlags(t *testing.T) {
+	ts := []struct {
+		sub  string
+		args []string
+	}{
+		{sub: "state", args: []string{"--no-such-flag=1", "web"}},
+		{sub: "state", args: []string{"--criu", "/usr/sbin/criu", "web"}},
+		{sub: "start", args: []string{"--debug", "web"}},
+		{sub: "kill", args: []string{"--all", "web", "KILL"}},
+		{sub: "delete", args: []string{"--force", "web"}},
+		{sub: "create", args: []string{"--bundle", ".", "--pid-file", "/run/web.pid", "--pidfd-socket", "/run/web.sock", "--no-such-flag=1", "web"}},
+	}
+
+	for _, tt := range ts {
+		cmd := NewCMD(context.Background())
+		sub, _, err := cmd.Find([]string{tt.sub})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sub.ParseFlags(tt.args); err != nil {
+			t.Fatalf("[%s %v] want unknown flags to be ignored, got %v", tt.sub, tt.args, err)
+		}
+		if got := sub.Flags().Args(); len(got) == 0 || got[0] != "web" {
+			t.Fatalf("[%s %v] want container id to be kept, got args %s", tt.sub, tt.args, strings.Join(got, " "))
+		}
+	}
+}
@@ -32,35 +32,47 @@ package start
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 
 	aeCMD "github.com/aurae-runtime/ae/cmd"
-	"github.com/aurae-runtime/ae/pkg/cli"
-	"github.com/aurae-runtime/ae/pkg/cli/printer"
+	"github.com/aurae-runtime/ae/pkg/client"
+	"github.com/aurae-runtime/ae/pkg/config"
+	"github.com/aurae-runtime/ae/pkg/oci"
 	"github.com/spf13/cobra"
 )
 
 type option struct {
 	aeCMD.Option
-	writer       io.Writer
-	outputFormat *cli.OutputFormat
+	id      string
+	auth    *config.Auth
+	globals *oci.Globals
+	writer  io.Writer
 }
 
-func (o *option) Complete(_ []string) error {
+func (o *option) Complete(args []string) error {
+	if len(args) != 1 {
+		return errors.New("expected container id to be passed to this command")
+	}
+	o.id = args[0]
 	return nil
 }
 
 func (o *option) Validate() error {
-	if err := o.outputFormat.Validate(); err != nil {
-		return err
-	}
-	return nil
+	return oci.ValidateID(o.id)
 }
 
-func (o *option) Execute(_ context.Context) error {
-	fmt.Fprintln(o.writer, "start called")
-	return nil
+func (o *option) Execute(ctx context.Context) error {
+	c, err := client.New(ctx, config.WithAuth(*o.auth))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	cells, err := c.Cells()
+	if err != nil {
+		return err
+	}
+	return oci.NewRuntime(o.globals.Store(), cells).Start(ctx, o.id)
 }
 
 func (o *option) SetWriter(writer io.Writer) {
@@ -69,19 +81,21 @@ func (o *option) SetWriter(writer io.Writer) {
 
 func NewCMD(ctx context.Context) *cobra.Command {
 	o := &option{
-		outputFormat: cli.NewOutputFormat().
-			WithPrinter(printer.NewJSON()).
-			WithPrinter(printer.NewYAML()),
+		auth: &config.Auth{},
 	}
 	cmd := &cobra.Command{
-		Use:   "start",
+		Use:   "start <container-id>",
 		Short: "Start the user-specified code from process.",
-		Long:  `Start the user-specified code from process.`,
+		Long: `Start the user-specified code from process.
+
+Only containers in the created state can be started.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return aeCMD.Run(ctx, o, cmd, args)
+			o.globals = oci.GlobalsFrom(cmd)
+			return oci.LogError(aeCMD.Run(ctx, o, cmd, args))
 		},
 	}
-	o.outputFormat.AddFlags(cmd)
+	o.auth.AddFlags(cmd)
 
 	return cmd
 }
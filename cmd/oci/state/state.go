@@ -32,35 +32,61 @@ package state
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 
 	aeCMD "github.com/aurae-runtime/ae/cmd"
-	"github.com/aurae-runtime/ae/pkg/cli"
-	"github.com/aurae-runtime/ae/pkg/cli/printer"
+	"github.com/aurae-runtime/ae/pkg/client"
+	"github.com/aurae-runtime/ae/pkg/config"
+	"github.com/aurae-runtime/ae/pkg/oci"
 	"github.com/spf13/cobra"
 )
 
 type option struct {
 	aeCMD.Option
-	writer       io.Writer
-	outputFormat *cli.OutputFormat
+	id      string
+	auth    *config.Auth
+	globals *oci.Globals
+	writer  io.Writer
 }
 
-func (o *option) Complete(_ []string) error {
+func (o *option) Complete(args []string) error {
+	if len(args) != 1 {
+		return errors.New("expected container id to be passed to this command")
+	}
+	o.id = args[0]
 	return nil
 }
 
 func (o *option) Validate() error {
-	if err := o.outputFormat.Validate(); err != nil {
+	return oci.ValidateID(o.id)
+}
+
+func (o *option) Execute(ctx context.Context) error {
+	c, err := client.New(ctx, config.WithAuth(*o.auth))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	cells, err := c.Cells()
+	if err != nil {
 		return err
 	}
-	return nil
-}
 
-func (o *option) Execute(_ context.Context) error {
-	fmt.Fprintln(o.writer, "state called")
-	return nil
+	state, err := oci.NewRuntime(o.globals.Store(), cells).State(ctx, o.id)
+	if err != nil {
+		return err
+	}
+
+	// The state is consumed by tooling expecting the exact runtime-spec
+	// schema, so it is not passed through the generic printers.
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(o.writer, string(b))
+	return err
 }
 
 func (o *option) SetWriter(writer io.Writer) {
@@ -69,19 +95,22 @@ func (o *option) SetWriter(writer io.Writer) {
 
 func NewCMD(ctx context.Context) *cobra.Command {
 	o := &option{
-		outputFormat: cli.NewOutputFormat().
-			WithPrinter(printer.NewJSON()).
-			WithPrinter(printer.NewYAML()),
+		auth: &config.Auth{},
 	}
 	cmd := &cobra.Command{
-		Use:   "state",
+		Use:   "state <container-id>",
 		Short: "Request the container state.",
-		Long:  `Request the container state.`,
+		Long: `Request the container state.
+
+The state is printed as the JSON document defined by the OCI runtime
+specification, with a status of creating, created, running or stopped.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return aeCMD.Run(ctx, o, cmd, args)
+			o.globals = oci.GlobalsFrom(cmd)
+			return oci.LogError(aeCMD.Run(ctx, o, cmd, args))
 		},
 	}
-	o.outputFormat.AddFlags(cmd)
+	o.auth.AddFlags(cmd)
 
 	return cmd
 }
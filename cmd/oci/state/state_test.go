@@ -0,0 +1,54 @@
+package state
+
+import (
+	"testing"
+)
+
+func TestComplete(t *testing.T) {
+	ts := []struct {
+		args    []string
+		wantid  string
+		wanterr bool
+	}{
+		{args: []string{}, wanterr: true},
+		{args: []string{"web"}, wantid: "web"},
+		{args: []string{"web", "db"}, wanterr: true},
+	}
+
+	for _, tt := range ts {
+		o := &option{}
+		goterr := o.Complete(tt.args)
+		if tt.wanterr && goterr == nil {
+			t.Fatal("want error, got no error")
+		}
+		if !tt.wanterr && goterr != nil {
+			t.Fatalf("want no error, got error %q", goterr)
+		}
+		if o.id != tt.wantid {
+			t.Fatalf("want id %q, got id %q", tt.wantid, o.id)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	ts := []struct {
+		name    string
+		id      string
+		wanterr bool
+	}{
+		{name: "valid", id: "web"},
+		{name: "no id", wanterr: true},
+		{name: "invalid id", id: "../web", wanterr: true},
+	}
+
+	for _, tt := range ts {
+		o := &option{id: tt.id}
+		goterr := o.Validate()
+		if tt.wanterr && goterr == nil {
+			t.Fatalf("[%s] want error, got no error", tt.name)
+		}
+		if !tt.wanterr && goterr != nil {
+			t.Fatalf("[%s] want no error, got error: %s", tt.name, goterr)
+		}
+	}
+}
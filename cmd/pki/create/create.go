@@ -32,8 +32,10 @@ package create
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	aeCMD "github.com/aurae-runtime/ae/cmd"
 	"github.com/aurae-runtime/ae/pkg/cli"
@@ -48,6 +50,14 @@ type option struct {
 	directory    string
 	domain       string
 	user         string
+	keyTypeName  string
+	templateFile string
+	subject      string
+	days         uint
+	daysSet      bool
+	notAfter     string
+	maxPathLen   int
+	profile      pki.Profile
 	silent       bool
 	writer       io.Writer
 }
@@ -62,16 +72,63 @@ func (o *option) Complete(args []string) error {
 
 	o.domain = args[0]
 
+	template := &pki.Template{}
+	if o.templateFile != "" {
+		var err error
+		if template, err = pki.LoadTemplate(o.templateFile); err != nil {
+			return err
+		}
+	}
+	o.profile = template.CA
+	if o.user != "" {
+		o.profile = template.Client
+	}
+
+	keyType, err := pki.ParseKeyType(o.keyTypeName)
+	if err != nil {
+		return err
+	}
+	if keyType != "" {
+		o.profile.KeyType = keyType
+	}
+	if o.subject != "" {
+		if o.profile.Subject, err = pki.ParseSubject(o.subject); err != nil {
+			return err
+		}
+	}
+	if o.daysSet {
+		o.profile.Validity = pki.Duration(time.Duration(o.days) * 24 * time.Hour)
+		o.profile.NotAfter = time.Time{}
+	}
+	if o.notAfter != "" {
+		if o.profile.NotAfter, err = pki.ParseNotAfter(o.notAfter); err != nil {
+			return err
+		}
+		o.profile.Validity = 0
+	}
+	if o.maxPathLen >= 0 {
+		o.profile.MaxPathLen = &o.maxPathLen
+	}
+
 	return nil
 }
 
 func (o *option) Validate() error {
+	if o.daysSet && o.notAfter != "" {
+		return errors.New("--days and --not-after are mutually exclusive")
+	}
+	if o.daysSet && o.days == 0 {
+		return errors.New("--days must be at least 1")
+	}
+	if o.user != "" && (o.daysSet || o.notAfter != "" || o.maxPathLen >= 0) {
+		return errors.New("--days, --not-after and --max-path-len only apply to the CA")
+	}
 	return nil
 }
 
 func (o *option) Execute(_ context.Context) error {
 	if o.user != "" {
-		clientCSR, err := pki.HandleCreateClientCSR(o.directory, o.domain, o.user)
+		clientCSR, err := pki.HandleCreateClientCSR(o.directory, o.domain, o.user, o.profile)
 		if err != nil {
 			return fmt.Errorf("failed to create client csr: %w", err)
 		}
@@ -82,7 +139,7 @@ func (o *option) Execute(_ context.Context) error {
 		return nil
 	}
 
-	rootCA, err := pki.HandleCreateAuraeRootCA(o.directory, o.domain)
+	rootCA, err := pki.HandleCreateAuraeRootCA(o.directory, o.domain, o.profile)
 	if err != nil {
 		return fmt.Errorf("failed to create aurae root ca: %w", err)
 	}
@@ -102,15 +159,21 @@ func NewCMD(ctx context.Context) *cobra.Command {
 			WithDefaultFormat(printer.NewJSON().Format()).
 			WithPrinter(printer.NewJSON()).
 			WithPrinter(printer.NewYAML()),
-		silent: false,
+		days:       uint(pki.DefaultCAValidity / (24 * time.Hour)),
+		maxPathLen: -1,
+		silent:     false,
 	}
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Creates a CA for auraed.",
 		Example: `ae pki create my.domain.com
-ae pki create --dir ./pki/ my.domain.com`,
+ae pki create --dir ./pki/ my.domain.com
+ae pki create --key-type ecdsa-p256 --dir ./pki/ my.domain.com
+ae pki create --subject "/O=Acme/C=DE" --days 1825 --max-path-len 0 --dir ./pki/ my.domain.com
+ae pki create --template pki.yaml --dir ./pki/ my.domain.com`,
 
 		RunE: func(cmd *cobra.Command, args []string) error {
+			o.daysSet = cmd.Flags().Changed("days")
 			return aeCMD.Run(ctx, o, cmd, args)
 		},
 	}
@@ -118,6 +181,12 @@ ae pki create --dir ./pki/ my.domain.com`,
 	o.outputFormat.AddFlags(cmd)
 	cmd.Flags().StringVarP(&o.directory, "dir", "d", o.directory, "Output directory to store CA files.")
 	cmd.Flags().StringVarP(&o.user, "user", "u", o.user, "Creates client certificate for a given user.")
+	cmd.Flags().StringVar(&o.keyTypeName, "key-type", o.keyTypeName, "Type of the private key: rsa2048, rsa4096, ecdsa-p256, ecdsa-p384 or ed25519. Defaults to rsa2048 for the CA and rsa4096 for client keys.")
+	cmd.Flags().StringVar(&o.templateFile, "template", o.templateFile, "YAML template configuring subject, validity, constraints and key usages. Flags take precedence.")
+	cmd.Flags().StringVar(&o.subject, "subject", o.subject, "Subject without common name in openssl format, e.g. /O=Acme/OU=Platform/C=DE.")
+	cmd.Flags().UintVar(&o.days, "days", o.days, "Number of days the CA is valid.")
+	cmd.Flags().StringVar(&o.notAfter, "not-after", o.notAfter, "Date until the CA is valid, as YYYY-MM-DD or RFC 3339 timestamp.")
+	cmd.Flags().IntVar(&o.maxPathLen, "max-path-len", o.maxPathLen, "Maximum number of intermediate CAs below the CA. Negative means unlimited.")
 	cmd.Flags().BoolVarP(&o.silent, "silent", "s", o.silent, "Silent mode, omits output")
 
 	return cmd
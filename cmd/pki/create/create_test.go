@@ -37,6 +37,7 @@ import (
 	"encoding/json"
 	"encoding/pem"
 	"testing"
+	"time"
 
 	"github.com/aurae-runtime/ae/pkg/pki"
 )
@@ -70,4 +71,52 @@ func TestPKICreateCMD(t *testing.T) {
 			t.Errorf("certificate does not contain common name")
 		}
 	})
+	t.Run("ae pki create --subject /O=Acme/C=DE --days 30 --max-path-len 0 my.domain.com", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		cmd := NewCMD(context.Background())
+		cmd.SetOut(buffer)
+		cmd.SetErr(buffer)
+		cmd.SetArgs([]string{"--subject", "/O=Acme/C=DE", "--days", "30", "--max-path-len", "0", "my.domain.com"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("ae pki create failed: %s", err)
+		}
+
+		var ca pki.Certificate
+		if err := json.Unmarshal(buffer.Bytes(), &ca); err != nil {
+			t.Fatalf("could not unmarshal certificate: %s", err)
+		}
+		crt, err := ca.GetCertificate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if crt.Subject.String() != "CN=my.domain.com,O=Acme,C=DE" {
+			t.Errorf("unexpected subject %s", crt.Subject)
+		}
+		if d := crt.NotAfter.Sub(crt.NotBefore); d != 30*24*time.Hour {
+			t.Errorf("want validity of 30 days, got %s", d)
+		}
+		if !crt.MaxPathLenZero {
+			t.Error("want max path length 0")
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	ts := []struct {
+		name    string
+		o       option
+		wanterr bool
+	}{
+		{name: "CA", o: option{daysSet: true, days: 30, maxPathLen: -1}},
+		{name: "days and not after", o: option{daysSet: true, days: 30, notAfter: "2030-01-01", maxPathLen: -1}, wanterr: true},
+		{name: "zero days", o: option{daysSet: true, maxPathLen: -1}, wanterr: true},
+		{name: "validity of csr", o: option{user: "nova", notAfter: "2030-01-01", maxPathLen: -1}, wanterr: true},
+		{name: "path length of csr", o: option{user: "nova", maxPathLen: 0}, wanterr: true},
+	}
+
+	for _, tt := range ts {
+		if err := tt.o.Validate(); tt.wanterr != (err != nil) {
+			t.Fatalf("[%s] want error %v, got %v", tt.name, tt.wanterr, err)
+		}
+	}
 }
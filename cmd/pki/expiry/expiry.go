@@ -0,0 +1,128 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package expiry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	aeCMD "github.com/aurae-runtime/ae/cmd"
+	"github.com/aurae-runtime/ae/pkg/cli"
+	"github.com/aurae-runtime/ae/pkg/cli/printer"
+	"github.com/aurae-runtime/ae/pkg/pki"
+	"github.com/spf13/cobra"
+)
+
+type option struct {
+	aeCMD.Option
+	outputFormat *cli.OutputFormat
+	directory    string
+	withinValue  string
+	within       pki.Duration
+	writer       io.Writer
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) == 0 {
+		return errors.New("command 'expiry' requires a pki directory as argument")
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("too many arguments for command 'expiry', expect %d, got %d", 1, len(args))
+	}
+
+	o.directory = args[0]
+
+	within, err := pki.ParseDuration(o.withinValue)
+	if err != nil {
+		return fmt.Errorf("invalid --within: %w", err)
+	}
+	o.within = within
+	return nil
+}
+
+func (o *option) Validate() error {
+	if o.within < 0 {
+		return errors.New("--within must not be negative")
+	}
+	return o.outputFormat.Validate()
+}
+
+func (o *option) Execute(_ context.Context) error {
+	report, err := pki.CheckExpiry(o.directory, o.within)
+	if err != nil {
+		return err
+	}
+	if err := o.outputFormat.ToPrinter().Print(o.writer, report); err != nil {
+		return err
+	}
+	if !report.OK() {
+		return fmt.Errorf("certificates in %s expire within %s", o.directory, o.within)
+	}
+	return nil
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func NewCMD(ctx context.Context) *cobra.Command {
+	o := &option{
+		outputFormat: cli.NewOutputFormat().
+			WithDefaultFormat(printer.NewText().Format()).
+			WithPrinter(printer.NewText()).
+			WithPrinter(printer.NewJSON()).
+			WithPrinter(printer.NewYAML()),
+		withinValue: pki.DefaultRenewWithin.String(),
+	}
+	cmd := &cobra.Command{
+		Use:   "expiry [dir]",
+		Short: "Reports certificates of a PKI directory that expire soon.",
+		Long: `Reports certificates of a PKI directory that expire soon.
+
+The CA, the server certificate and the signed client certificates are
+checked. The command fails if any of them is expired or expires within the
+threshold, so it can run from cron or a monitoring check. Use
+'ae pki renew' to renew them.`,
+		Example: `ae pki expiry ~/.aurae/pki/
+ae pki expiry --within 90d -o json ~/.aurae/pki/`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return aeCMD.Run(ctx, o, cmd, args)
+		},
+	}
+
+	o.outputFormat.AddFlags(cmd)
+	cmd.Flags().StringVar(&o.withinValue, "within", o.withinValue, "Threshold for certificates to count as expiring, for example 30d or 12h.")
+
+	return cmd
+}
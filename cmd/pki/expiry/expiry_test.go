@@ -0,0 +1,87 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package expiry
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/aurae-runtime/ae/pkg/pki"
+)
+
+func TestPKIExpiryCMD(t *testing.T) {
+	dir := t.TempDir()
+	profile := pki.Profile{KeyType: pki.KeyTypeECDSAP256}
+	template := pki.Template{CA: profile, Server: profile, Client: profile}
+	if _, err := pki.HandleInit(dir, "unsafe.aurae.io", pki.InitOptions{Users: []string{"nova"}, Template: template}); err != nil {
+		t.Fatalf("could not initialize pki: %s", err)
+	}
+
+	buffer := &bytes.Buffer{}
+	cmd := NewCMD(context.Background())
+	cmd.SetOut(buffer)
+	cmd.SetErr(buffer)
+	cmd.SetArgs([]string{dir})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("ae pki expiry failed: %s", err)
+	}
+
+	cmd = NewCMD(context.Background())
+	cmd.SetOut(buffer)
+	cmd.SetErr(buffer)
+	cmd.SetArgs([]string{"--within", "400d", dir})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("want error for certificates expiring within 400 days, got no error")
+	}
+}
+
+func TestComplete(t *testing.T) {
+	ts := []struct {
+		args    []string
+		within  string
+		wanterr bool
+	}{
+		{args: []string{"pki"}, within: "30d"},
+		{args: []string{"pki"}, within: "12h"},
+		{args: []string{"pki"}, within: "a month", wanterr: true},
+		{args: []string{}, within: "30d", wanterr: true},
+		{args: []string{"a", "b"}, within: "30d", wanterr: true},
+	}
+
+	for _, tt := range ts {
+		o := &option{withinValue: tt.within}
+		err := o.Complete(tt.args)
+		if tt.wanterr != (err != nil) {
+			t.Fatalf("%v %q: want error %v, got %v", tt.args, tt.within, tt.wanterr, err)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package initialize
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	aeCMD "github.com/aurae-runtime/ae/cmd"
+	"github.com/aurae-runtime/ae/pkg/cli"
+	"github.com/aurae-runtime/ae/pkg/cli/printer"
+	"github.com/aurae-runtime/ae/pkg/pki"
+	"github.com/spf13/cobra"
+)
+
+type option struct {
+	aeCMD.Option
+	outputFormat *cli.OutputFormat
+	directory    string
+	domain       string
+	users        []string
+	dnsNames     []string
+	ipAddresses  []string
+	ips          []net.IP
+	keyTypeName  string
+	templateFile string
+	subject      string
+	template     pki.Template
+	silent       bool
+	writer       io.Writer
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("command 'init' requires a domain name as argument")
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("too many arguments for command 'init', expect %d, got %d", 1, len(args))
+	}
+
+	o.domain = args[0]
+
+	o.ips = nil
+	for _, s := range o.ipAddresses {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return fmt.Errorf("failed to parse ip %q", s)
+		}
+		o.ips = append(o.ips, ip)
+	}
+
+	o.template = pki.Template{}
+	if o.templateFile != "" {
+		template, err := pki.LoadTemplate(o.templateFile)
+		if err != nil {
+			return err
+		}
+		o.template = *template
+	}
+
+	keyType, err := pki.ParseKeyType(o.keyTypeName)
+	if err != nil {
+		return err
+	}
+	if keyType != "" {
+		o.template.SetKeyType(keyType)
+	}
+	if o.subject != "" {
+		subject, err := pki.ParseSubject(o.subject)
+		if err != nil {
+			return err
+		}
+		o.template.SetSubject(subject)
+	}
+
+	return nil
+}
+
+func (o *option) Validate() error {
+	if err := o.outputFormat.Validate(); err != nil {
+		return err
+	}
+	if o.directory == "" {
+		return errors.New("--dir must not be empty")
+	}
+	return nil
+}
+
+func (o *option) Execute(_ context.Context) error {
+	summary, err := pki.HandleInit(o.directory, o.domain, pki.InitOptions{
+		Users:    o.users,
+		DNSNames: o.dnsNames,
+		IPs:      o.ips,
+		Template: o.template,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize pki: %w", err)
+	}
+	if !o.silent {
+		o.outputFormat.ToPrinter().Print(o.writer, summary)
+	}
+	return nil
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func NewCMD(ctx context.Context) *cobra.Command {
+	o := &option{
+		outputFormat: cli.NewOutputFormat().
+			WithDefaultFormat(printer.NewText().Format()).
+			WithPrinter(printer.NewText()).
+			WithPrinter(printer.NewJSON()).
+			WithPrinter(printer.NewYAML()),
+		directory: "pki",
+		users:     []string{"nova"},
+		silent:    false,
+	}
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Creates a complete PKI for auraed and ae.",
+		Long: `Creates a complete PKI for auraed and ae.
+
+The CA, the server certificate of auraed and a signed client certificate for
+every user are created in the output directory, using the file names auraed
+and ae load by default. Private keys are only readable by their owner.
+
+Existing files are reused, so the command can be run again to add users. The
+CA is never overwritten; server and client certificates are issued again if
+they are expired or were not signed by the CA.`,
+		Example: `ae pki init unsafe.aurae.io
+ae pki init --dir ~/.aurae/pki/ --user nova --user ops unsafe.aurae.io
+ae pki init --key-type ecdsa-p256 unsafe.aurae.io
+ae pki init --subject "/O=Acme/C=DE" --template pki.yaml unsafe.aurae.io`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return aeCMD.Run(ctx, o, cmd, args)
+		},
+	}
+
+	o.outputFormat.AddFlags(cmd)
+	cmd.Flags().StringVarP(&o.directory, "dir", "d", o.directory, "Output directory to store the PKI files.")
+	cmd.Flags().StringSliceVarP(&o.users, "user", "u", o.users, "User to create a client certificate for. Can be repeated or comma separated.")
+	cmd.Flags().StringSliceVar(&o.dnsNames, "dns", o.dnsNames, "DNS name the server certificate is valid for. Defaults to server.<domain>.")
+	cmd.Flags().StringSliceVar(&o.ipAddresses, "ip", o.ipAddresses, "IP address the server certificate is valid for.")
+	cmd.Flags().StringVar(&o.keyTypeName, "key-type", o.keyTypeName, "Type of the created private keys: rsa2048, rsa4096, ecdsa-p256, ecdsa-p384 or ed25519. Defaults to rsa2048 for the CA and rsa4096 otherwise.")
+	cmd.Flags().StringVar(&o.templateFile, "template", o.templateFile, "YAML template configuring subject, validity, constraints and key usages of the certificates. Flags take precedence.")
+	cmd.Flags().StringVar(&o.subject, "subject", o.subject, "Subject of all certificates without common name in openssl format, e.g. /O=Acme/OU=Platform/C=DE.")
+	cmd.Flags().BoolVarP(&o.silent, "silent", "s", o.silent, "Silent mode, omits output")
+
+	return cmd
+}
@@ -0,0 +1,77 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package initialize
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/aurae-runtime/ae/pkg/pki"
+)
+
+func TestPKIInitCMD(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "pki")
+	buffer := &bytes.Buffer{}
+	cmd := NewCMD(context.Background())
+	cmd.SetOut(buffer)
+	cmd.SetErr(buffer)
+	cmd.SetArgs([]string{"--dir", dir, "--user", "ops", "-o", "json", "unsafe.aurae.io"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("ae pki init failed: %s", err)
+	}
+
+	var summary pki.InitSummary
+	if err := json.Unmarshal(buffer.Bytes(), &summary); err != nil {
+		t.Fatalf("could not unmarshal summary: %s", err)
+	}
+	if len(summary.Certificates) != 3 || summary.Certificates[2].File != filepath.Join(dir, "_signed.client.ops.crt") {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	o := &option{ipAddresses: []string{"10.0.0.1"}}
+	if err := o.Complete([]string{"unsafe.aurae.io"}); err != nil || len(o.ips) != 1 {
+		t.Fatalf("want one ip and no error, got %v (%v)", o.ips, err)
+	}
+	if err := (&option{ipAddresses: []string{"invalid ip"}}).Complete([]string{"unsafe.aurae.io"}); err == nil {
+		t.Fatal("want error for invalid ip, got no error")
+	}
+	if err := (&option{}).Complete(nil); err == nil {
+		t.Fatal("want error for missing domain, got no error")
+	}
+	if err := (&option{keyTypeName: "dsa"}).Complete([]string{"unsafe.aurae.io"}); err == nil {
+		t.Fatal("want error for unsupported key type, got no error")
+	}
+}
@@ -0,0 +1,113 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package inspect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	aeCMD "github.com/aurae-runtime/ae/cmd"
+	"github.com/aurae-runtime/ae/pkg/cli"
+	"github.com/aurae-runtime/ae/pkg/cli/printer"
+	"github.com/aurae-runtime/ae/pkg/pki"
+	"github.com/spf13/cobra"
+)
+
+type option struct {
+	aeCMD.Option
+	outputFormat *cli.OutputFormat
+	file         string
+	directory    string
+	writer       io.Writer
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) == 0 {
+		return errors.New("command 'inspect' requires a file as argument")
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("too many arguments for command 'inspect', expect %d, got %d", 1, len(args))
+	}
+
+	o.file = args[0]
+	return nil
+}
+
+func (o *option) Validate() error {
+	return o.outputFormat.Validate()
+}
+
+func (o *option) Execute(_ context.Context) error {
+	inspection, err := pki.InspectFile(o.file, o.directory)
+	if err != nil {
+		return err
+	}
+	return o.outputFormat.ToPrinter().Print(o.writer, inspection)
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func NewCMD(ctx context.Context) *cobra.Command {
+	o := &option{
+		outputFormat: cli.NewOutputFormat().
+			WithDefaultFormat(printer.NewJSON().Format()).
+			WithPrinter(printer.NewJSON()).
+			WithPrinter(printer.NewYAML()),
+	}
+	cmd := &cobra.Command{
+		Use:   "inspect [file]",
+		Short: "Shows the contents of certificates, certificate requests and keys.",
+		Long: `Shows the contents of certificates, certificate requests and keys.
+
+Every PEM block in the file is decoded, so bundles holding a chain are shown
+in full. Certificates show subject, issuer, serial number, validity, SANs,
+key usages, key identifiers, public key and SHA-256 fingerprint.
+
+Private keys are never printed. Instead their public key is matched against
+the certificates and certificate requests in the directory of the key, or
+the directory passed with --dir.`,
+		Example: `ae pki inspect ~/.aurae/pki/_signed.client.nova.crt
+ae pki inspect -o yaml ~/.aurae/pki/client.nova.key`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return aeCMD.Run(ctx, o, cmd, args)
+		},
+	}
+
+	o.outputFormat.AddFlags(cmd)
+	cmd.Flags().StringVarP(&o.directory, "dir", "d", o.directory, "Directory to search for certificates matching a private key. Defaults to the directory of the file.")
+
+	return cmd
+}
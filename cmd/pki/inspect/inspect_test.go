@@ -0,0 +1,80 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package inspect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/aurae-runtime/ae/pkg/pki"
+)
+
+func TestPKIInspectCMD(t *testing.T) {
+	dir := t.TempDir()
+	profile := pki.Profile{KeyType: pki.KeyTypeECDSAP256}
+	template := pki.Template{CA: profile, Server: profile, Client: profile}
+	if _, err := pki.HandleInit(dir, "unsafe.aurae.io", pki.InitOptions{Users: []string{"nova"}, Template: template}); err != nil {
+		t.Fatalf("could not initialize pki: %s", err)
+	}
+
+	buffer := &bytes.Buffer{}
+	cmd := NewCMD(context.Background())
+	cmd.SetOut(buffer)
+	cmd.SetErr(buffer)
+	cmd.SetArgs([]string{filepath.Join(dir, "server.key")})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("ae pki inspect failed: %s", err)
+	}
+
+	var in pki.Inspection
+	if err := json.Unmarshal(buffer.Bytes(), &in); err != nil {
+		t.Fatalf("could not parse output %q: %s", buffer.String(), err)
+	}
+	if len(in.Keys) != 1 || len(in.Keys[0].Matches) != 1 || in.Keys[0].Matches[0].File != filepath.Join(dir, "_signed.server.crt") {
+		t.Fatalf("want server key to match server certificate, got %+v", in)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	o := &option{}
+	if err := o.Complete([]string{"ca.crt"}); err != nil || o.file != "ca.crt" {
+		t.Fatalf("want file ca.crt, got %q (%v)", o.file, err)
+	}
+	if err := (&option{}).Complete(nil); err == nil {
+		t.Fatal("want error for missing file, got no error")
+	}
+	if err := (&option{}).Complete([]string{"a.crt", "b.crt"}); err == nil {
+		t.Fatal("want error for too many files, got no error")
+	}
+}
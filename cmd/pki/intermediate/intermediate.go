@@ -0,0 +1,207 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package intermediate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	aeCMD "github.com/aurae-runtime/ae/cmd"
+	"github.com/aurae-runtime/ae/pkg/cli"
+	"github.com/aurae-runtime/ae/pkg/cli/printer"
+	"github.com/aurae-runtime/ae/pkg/pki"
+	"github.com/spf13/cobra"
+)
+
+type option struct {
+	aeCMD.Option
+	outputFormat *cli.OutputFormat
+	caDirectory  string
+	directory    string
+	commonName   string
+	days         uint
+	daysSet      bool
+	notAfter     string
+	maxPathLen   int
+	maxPathSet   bool
+	keyTypeName  string
+	templateFile string
+	subject      string
+	profile      pki.Profile
+	silent       bool
+	writer       io.Writer
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("command 'create-intermediate' requires a common name as argument")
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("too many arguments for command 'create-intermediate', expect %d, got %d", 1, len(args))
+	}
+
+	o.commonName = args[0]
+
+	o.profile = pki.Profile{}
+	if o.templateFile != "" {
+		template, err := pki.LoadTemplate(o.templateFile)
+		if err != nil {
+			return err
+		}
+		o.profile = template.Intermediate
+	}
+
+	keyType, err := pki.ParseKeyType(o.keyTypeName)
+	if err != nil {
+		return err
+	}
+	if keyType != "" {
+		o.profile.KeyType = keyType
+	}
+	if o.subject != "" {
+		if o.profile.Subject, err = pki.ParseSubject(o.subject); err != nil {
+			return err
+		}
+	}
+	if o.daysSet {
+		o.profile.Validity = pki.Duration(time.Duration(o.days) * 24 * time.Hour)
+		o.profile.NotAfter = time.Time{}
+	}
+	if o.notAfter != "" {
+		if o.profile.NotAfter, err = pki.ParseNotAfter(o.notAfter); err != nil {
+			return err
+		}
+		o.profile.Validity = 0
+	}
+	// unlike the root, intermediates may not issue CAs unless asked for
+	if o.maxPathSet || o.profile.MaxPathLen == nil {
+		o.profile.MaxPathLen = nil
+		if o.maxPathLen >= 0 {
+			o.profile.MaxPathLen = &o.maxPathLen
+		}
+	}
+
+	return nil
+}
+
+func (o *option) Validate() error {
+	if err := o.outputFormat.Validate(); err != nil {
+		return err
+	}
+	if o.caDirectory == "" {
+		return errors.New("--ca-dir must be passed to this command")
+	}
+	if o.directory == "" {
+		return errors.New("--dir must be passed to this command")
+	}
+	if filepath.Clean(o.directory) == filepath.Clean(o.caDirectory) {
+		return errors.New("--dir must differ from --ca-dir, the intermediate CA would replace its issuer")
+	}
+	if o.days == 0 {
+		return errors.New("--days must be at least 1")
+	}
+	if o.daysSet && o.notAfter != "" {
+		return errors.New("--days and --not-after are mutually exclusive")
+	}
+	return nil
+}
+
+func (o *option) Execute(_ context.Context) error {
+	ca, err := pki.LoadCA(o.caDirectory)
+	if err != nil {
+		return fmt.Errorf("failed to load CA: %w", err)
+	}
+
+	crt, err := pki.HandleCreateIntermediateCA(o.directory, ca, o.commonName, o.profile)
+	if err != nil {
+		return fmt.Errorf("failed to create intermediate CA: %w", err)
+	}
+	if !o.silent {
+		o.outputFormat.ToPrinter().Print(o.writer, crt)
+	}
+	return nil
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func NewCMD(ctx context.Context) *cobra.Command {
+	o := &option{
+		outputFormat: cli.NewOutputFormat().
+			WithDefaultFormat(printer.NewJSON().Format()).
+			WithPrinter(printer.NewJSON()).
+			WithPrinter(printer.NewYAML()),
+		days:       uint(pki.DefaultIntermediateValidity / (24 * time.Hour)),
+		maxPathLen: 0,
+		silent:     false,
+	}
+	cmd := &cobra.Command{
+		Use:   "create-intermediate",
+		Short: "Creates an intermediate CA signed by the CA.",
+		Long: `Creates an intermediate CA signed by the CA.
+
+The issuing CA is loaded from ca.crt and ca.key in the CA directory and may
+be an intermediate itself. The intermediate CA is written as ca.crt and
+ca.key to the output directory, where ca.crt holds the full chain up to the
+root. Pass the output directory as --ca-dir to 'ae pki create-server' and
+'ae pki sign' to issue certificates from the intermediate; they contain the
+intermediates, so peers only need to trust the root.
+
+By default the intermediate may not issue further CAs, pass a positive
+--max-path-len to allow it.`,
+		Example: `ae pki create-intermediate --ca-dir ./pki/ --dir ./cluster-1/ cluster-1.my.domain.com
+ae pki sign --ca-dir ./cluster-1/ ./cluster-1/client.nova.csr`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.daysSet = cmd.Flags().Changed("days")
+			o.maxPathSet = cmd.Flags().Changed("max-path-len")
+			return aeCMD.Run(ctx, o, cmd, args)
+		},
+	}
+
+	o.outputFormat.AddFlags(cmd)
+	cmd.Flags().StringVar(&o.caDirectory, "ca-dir", o.caDirectory, "Directory containing ca.crt and ca.key of the issuing CA.")
+	cmd.Flags().StringVarP(&o.directory, "dir", "d", o.directory, "Output directory to store the intermediate CA.")
+	cmd.Flags().UintVar(&o.days, "days", o.days, "Number of days the intermediate CA is valid, limited by the validity of the issuing CA.")
+	cmd.Flags().StringVar(&o.notAfter, "not-after", o.notAfter, "Date until the intermediate CA is valid, as YYYY-MM-DD or RFC 3339 timestamp.")
+	cmd.Flags().IntVar(&o.maxPathLen, "max-path-len", o.maxPathLen, "Maximum number of intermediate CAs below the intermediate. Negative means unlimited.")
+	cmd.Flags().StringVar(&o.keyTypeName, "key-type", o.keyTypeName, "Type of the private key: rsa2048, rsa4096, ecdsa-p256, ecdsa-p384 or ed25519. Defaults to rsa2048.")
+	cmd.Flags().StringVar(&o.templateFile, "template", o.templateFile, "YAML template whose intermediate profile configures the CA. Flags take precedence.")
+	cmd.Flags().StringVar(&o.subject, "subject", o.subject, "Subject without common name in openssl format, e.g. /O=Acme/OU=Platform/C=DE.")
+	cmd.Flags().BoolVarP(&o.silent, "silent", "s", o.silent, "Silent mode, omits output")
+
+	return cmd
+}
@@ -0,0 +1,106 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package intermediate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aurae-runtime/ae/pkg/cli"
+	"github.com/aurae-runtime/ae/pkg/cli/printer"
+)
+
+func TestComplete(t *testing.T) {
+	dir := t.TempDir()
+	template := filepath.Join(dir, "template.yaml")
+	if err := os.WriteFile(template, []byte("intermediate:\n  maxPathLen: 2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := []struct {
+		name        string
+		template    string
+		maxPathLen  int
+		maxPathSet  bool
+		wantPathLen int // -1 means unlimited
+	}{
+		{name: "default", maxPathLen: 0, wantPathLen: 0},
+		{name: "flag", maxPathLen: 1, maxPathSet: true, wantPathLen: 1},
+		{name: "unlimited", maxPathLen: -1, maxPathSet: true, wantPathLen: -1},
+		{name: "template", template: template, maxPathLen: 0, wantPathLen: 2},
+		{name: "flag overrides template", template: template, maxPathLen: 0, maxPathSet: true, wantPathLen: 0},
+	}
+
+	for _, tt := range ts {
+		o := &option{templateFile: tt.template, maxPathLen: tt.maxPathLen, maxPathSet: tt.maxPathSet}
+		if err := o.Complete([]string{"cluster-1.my.domain.com"}); err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		got := -1
+		if o.profile.MaxPathLen != nil {
+			got = *o.profile.MaxPathLen
+		}
+		if got != tt.wantPathLen {
+			t.Fatalf("%s: want max path length %d, got %d", tt.name, tt.wantPathLen, got)
+		}
+	}
+
+	if err := (&option{}).Complete(nil); err == nil {
+		t.Fatal("want error for missing common name, got no error")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	ts := []struct {
+		caDir   string
+		dir     string
+		wanterr bool
+	}{
+		{caDir: "pki", dir: "cluster-1"},
+		{caDir: "pki", dir: "./pki/", wanterr: true},
+		{caDir: "", dir: "cluster-1", wanterr: true},
+		{caDir: "pki", dir: "", wanterr: true},
+	}
+
+	for _, tt := range ts {
+		o := &option{
+			outputFormat: cli.NewOutputFormat().WithDefaultFormat(printer.NewJSON().Format()).WithPrinter(printer.NewJSON()),
+			caDirectory:  tt.caDir,
+			directory:    tt.dir,
+			days:         1,
+		}
+		err := o.Validate()
+		if tt.wanterr != (err != nil) {
+			t.Fatalf("%q %q: want error %v, got %v", tt.caDir, tt.dir, tt.wanterr, err)
+		}
+	}
+}
@@ -36,6 +36,14 @@ import (
 
 	aeCMD "github.com/aurae-runtime/ae/cmd"
 	pki_create "github.com/aurae-runtime/ae/cmd/pki/create"
+	pki_expiry "github.com/aurae-runtime/ae/cmd/pki/expiry"
+	pki_init "github.com/aurae-runtime/ae/cmd/pki/initialize"
+	pki_inspect "github.com/aurae-runtime/ae/cmd/pki/inspect"
+	pki_intermediate "github.com/aurae-runtime/ae/cmd/pki/intermediate"
+	pki_renew "github.com/aurae-runtime/ae/cmd/pki/renew"
+	pki_server "github.com/aurae-runtime/ae/cmd/pki/server"
+	pki_sign "github.com/aurae-runtime/ae/cmd/pki/sign"
+	pki_verify "github.com/aurae-runtime/ae/cmd/pki/verify"
 	"github.com/spf13/cobra"
 )
 
@@ -71,5 +79,13 @@ func NewCMD(ctx context.Context) *cobra.Command {
 		},
 	}
 	cmd.AddCommand(pki_create.NewCMD(ctx))
+	cmd.AddCommand(pki_expiry.NewCMD(ctx))
+	cmd.AddCommand(pki_init.NewCMD(ctx))
+	cmd.AddCommand(pki_inspect.NewCMD(ctx))
+	cmd.AddCommand(pki_intermediate.NewCMD(ctx))
+	cmd.AddCommand(pki_renew.NewCMD(ctx))
+	cmd.AddCommand(pki_server.NewCMD(ctx))
+	cmd.AddCommand(pki_sign.NewCMD(ctx))
+	cmd.AddCommand(pki_verify.NewCMD(ctx))
 	return cmd
 }
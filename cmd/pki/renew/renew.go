@@ -0,0 +1,141 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package renew
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	aeCMD "github.com/aurae-runtime/ae/cmd"
+	"github.com/aurae-runtime/ae/pkg/cli"
+	"github.com/aurae-runtime/ae/pkg/cli/printer"
+	"github.com/aurae-runtime/ae/pkg/pki"
+	"github.com/spf13/cobra"
+)
+
+type option struct {
+	aeCMD.Option
+	outputFormat *cli.OutputFormat
+	directory    string
+	caDirectory  string
+	withinValue  string
+	within       pki.Duration
+	rekey        bool
+	writer       io.Writer
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) == 0 {
+		return errors.New("command 'renew' requires a pki directory as argument")
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("too many arguments for command 'renew', expect %d, got %d", 1, len(args))
+	}
+
+	o.directory = args[0]
+
+	within, err := pki.ParseDuration(o.withinValue)
+	if err != nil {
+		return fmt.Errorf("invalid --within: %w", err)
+	}
+	o.within = within
+	return nil
+}
+
+func (o *option) Validate() error {
+	if o.within < 0 {
+		return errors.New("--within must not be negative")
+	}
+	return o.outputFormat.Validate()
+}
+
+func (o *option) Execute(_ context.Context) error {
+	report, err := pki.HandleRenew(o.directory, o.within, pki.RenewOptions{
+		CADirectory: o.caDirectory,
+		Rekey:       o.rekey,
+	})
+	if err != nil {
+		return err
+	}
+	if err := o.outputFormat.ToPrinter().Print(o.writer, report); err != nil {
+		return err
+	}
+	if !report.OK() {
+		return fmt.Errorf("failed to renew certificates in %s", o.directory)
+	}
+	return nil
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func NewCMD(ctx context.Context) *cobra.Command {
+	o := &option{
+		outputFormat: cli.NewOutputFormat().
+			WithDefaultFormat(printer.NewText().Format()).
+			WithPrinter(printer.NewText()).
+			WithPrinter(printer.NewJSON()).
+			WithPrinter(printer.NewYAML()),
+		withinValue: pki.DefaultRenewWithin.String(),
+	}
+	cmd := &cobra.Command{
+		Use:   "renew [dir]",
+		Short: "Renews certificates of a PKI directory that expire soon.",
+		Long: `Renews certificates of a PKI directory that expire soon.
+
+The server certificate and the signed client certificates that are expired
+or expire within the threshold are issued again by the CA. They keep their
+subject, SANs, key usages, lifetime and private key; --rekey generates new
+keys of the same type and new client certificate requests. Certificates
+without a key in the directory are renewed for their public key. The CA is
+never renewed, so certificates it cannot extend beyond the threshold are
+reported instead of renewed.
+
+Replaced files are kept as <file>.<timestamp>.bak next to the new ones. The
+CA is loaded from the PKI directory unless --ca-dir is passed.`,
+		Example: `ae pki renew ~/.aurae/pki/
+ae pki renew --within 60d --rekey --ca-dir ./cluster-1/ ./node-1/`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return aeCMD.Run(ctx, o, cmd, args)
+		},
+	}
+
+	o.outputFormat.AddFlags(cmd)
+	cmd.Flags().StringVar(&o.withinValue, "within", o.withinValue, "Renew certificates that expire within this threshold, for example 30d or 12h.")
+	cmd.Flags().StringVar(&o.caDirectory, "ca-dir", o.caDirectory, "Directory containing ca.crt and ca.key of the issuing CA. Defaults to the PKI directory.")
+	cmd.Flags().BoolVar(&o.rekey, "rekey", o.rekey, "Generate new private keys instead of keeping the existing ones.")
+
+	return cmd
+}
@@ -0,0 +1,91 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package renew
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aurae-runtime/ae/pkg/pki"
+)
+
+func TestPKIRenewCMD(t *testing.T) {
+	dir := t.TempDir()
+	profile := pki.Profile{KeyType: pki.KeyTypeECDSAP256}
+	template := pki.Template{CA: profile, Server: profile, Client: profile}
+	if _, err := pki.HandleInit(dir, "unsafe.aurae.io", pki.InitOptions{Users: []string{"nova"}, Template: template}); err != nil {
+		t.Fatalf("could not initialize pki: %s", err)
+	}
+	key, err := os.ReadFile(filepath.Join(dir, "server.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := &bytes.Buffer{}
+	cmd := NewCMD(context.Background())
+	cmd.SetOut(buffer)
+	cmd.SetErr(buffer)
+	cmd.SetArgs([]string{"--within", "400d", "--rekey", dir})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("ae pki renew failed: %s", err)
+	}
+
+	renewed, err := os.ReadFile(filepath.Join(dir, "server.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(key, renewed) {
+		t.Fatal("want new server key")
+	}
+	report, err := pki.VerifyDirectory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.OK() {
+		t.Fatalf("want renewed pki to verify, got %+v", report.Results)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	o := &option{withinValue: "60d"}
+	if err := o.Complete([]string{"pki"}); err != nil || o.directory != "pki" || o.within.String() != "60d" {
+		t.Fatalf("want directory pki and 60d, got %q and %s (%v)", o.directory, o.within, err)
+	}
+	if err := (&option{withinValue: "soon"}).Complete([]string{"pki"}); err == nil {
+		t.Fatal("want error for invalid threshold, got no error")
+	}
+	if err := (&option{withinValue: "30d"}).Complete(nil); err == nil {
+		t.Fatal("want error for missing directory, got no error")
+	}
+}
@@ -0,0 +1,202 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	aeCMD "github.com/aurae-runtime/ae/cmd"
+	"github.com/aurae-runtime/ae/pkg/cli"
+	"github.com/aurae-runtime/ae/pkg/cli/printer"
+	"github.com/aurae-runtime/ae/pkg/pki"
+	"github.com/spf13/cobra"
+)
+
+type option struct {
+	aeCMD.Option
+	outputFormat *cli.OutputFormat
+	caDirectory  string
+	directory    string
+	domain       string
+	dnsNames     []string
+	ipAddresses  []string
+	ips          []net.IP
+	days         uint
+	daysSet      bool
+	notAfter     string
+	keyTypeName  string
+	templateFile string
+	subject      string
+	profile      pki.Profile
+	silent       bool
+	writer       io.Writer
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("command 'create-server' requires a domain name as argument")
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("too many arguments for command 'create-server', expect %d, got %d", 1, len(args))
+	}
+
+	o.domain = args[0]
+	if o.directory == "" {
+		o.directory = o.caDirectory
+	}
+
+	o.ips = nil
+	for _, s := range o.ipAddresses {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return fmt.Errorf("failed to parse ip %q", s)
+		}
+		o.ips = append(o.ips, ip)
+	}
+
+	o.profile = pki.Profile{}
+	if o.templateFile != "" {
+		template, err := pki.LoadTemplate(o.templateFile)
+		if err != nil {
+			return err
+		}
+		o.profile = template.Server
+	}
+
+	keyType, err := pki.ParseKeyType(o.keyTypeName)
+	if err != nil {
+		return err
+	}
+	if keyType != "" {
+		o.profile.KeyType = keyType
+	}
+	if o.subject != "" {
+		if o.profile.Subject, err = pki.ParseSubject(o.subject); err != nil {
+			return err
+		}
+	}
+	if o.daysSet {
+		o.profile.Validity = pki.Duration(time.Duration(o.days) * 24 * time.Hour)
+		o.profile.NotAfter = time.Time{}
+	}
+	if o.notAfter != "" {
+		if o.profile.NotAfter, err = pki.ParseNotAfter(o.notAfter); err != nil {
+			return err
+		}
+		o.profile.Validity = 0
+	}
+
+	return nil
+}
+
+func (o *option) Validate() error {
+	if err := o.outputFormat.Validate(); err != nil {
+		return err
+	}
+	if o.caDirectory == "" {
+		return errors.New("--ca-dir must be passed to this command")
+	}
+	if o.days == 0 {
+		return errors.New("--days must be at least 1")
+	}
+	if o.daysSet && o.notAfter != "" {
+		return errors.New("--days and --not-after are mutually exclusive")
+	}
+	return nil
+}
+
+func (o *option) Execute(_ context.Context) error {
+	ca, err := pki.LoadCA(o.caDirectory)
+	if err != nil {
+		return fmt.Errorf("failed to load CA: %w", err)
+	}
+
+	crt, err := pki.HandleCreateServerCertificate(o.directory, ca, o.domain, o.dnsNames, o.ips, o.profile)
+	if err != nil {
+		return fmt.Errorf("failed to create server certificate: %w", err)
+	}
+	if !o.silent {
+		o.outputFormat.ToPrinter().Print(o.writer, crt)
+	}
+	return nil
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func NewCMD(ctx context.Context) *cobra.Command {
+	o := &option{
+		outputFormat: cli.NewOutputFormat().
+			WithDefaultFormat(printer.NewJSON().Format()).
+			WithPrinter(printer.NewJSON()).
+			WithPrinter(printer.NewYAML()),
+		days:   uint(pki.DefaultServerValidity / (24 * time.Hour)),
+		silent: false,
+	}
+	cmd := &cobra.Command{
+		Use:   "create-server",
+		Short: "Creates a server certificate for auraed signed by the CA.",
+		Long: `Creates a server certificate for auraed signed by the CA.
+
+The CA is loaded from ca.crt and ca.key in the CA directory. The certificate
+and key are written as _signed.server.crt and server.key, the files auraed
+loads by default. Without --dns the certificate is valid for
+server.<domain>, the name ae verifies by default.`,
+		Example: `ae pki create-server --ca-dir ./pki/ my.domain.com
+ae pki create-server --ca-dir ./pki/ --dir ./node-1/ --dns node-1.my.domain.com --ip 10.0.0.1 my.domain.com
+ae pki create-server --ca-dir ./pki/ --template pki.yaml my.domain.com`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.daysSet = cmd.Flags().Changed("days")
+			return aeCMD.Run(ctx, o, cmd, args)
+		},
+	}
+
+	o.outputFormat.AddFlags(cmd)
+	cmd.Flags().StringVar(&o.caDirectory, "ca-dir", o.caDirectory, "Directory containing ca.crt and ca.key.")
+	cmd.Flags().StringVarP(&o.directory, "dir", "d", o.directory, "Output directory to store the certificate and key. Defaults to the CA directory.")
+	cmd.Flags().StringSliceVar(&o.dnsNames, "dns", o.dnsNames, "DNS name the certificate is valid for. Can be repeated or comma separated.")
+	cmd.Flags().StringSliceVar(&o.ipAddresses, "ip", o.ipAddresses, "IP address the certificate is valid for. Can be repeated or comma separated.")
+	cmd.Flags().UintVar(&o.days, "days", o.days, "Number of days the certificate is valid, limited by the validity of the CA.")
+	cmd.Flags().StringVar(&o.notAfter, "not-after", o.notAfter, "Date until the certificate is valid, as YYYY-MM-DD or RFC 3339 timestamp.")
+	cmd.Flags().StringVar(&o.templateFile, "template", o.templateFile, "YAML template whose server profile configures the certificate. Flags take precedence.")
+	cmd.Flags().StringVar(&o.subject, "subject", o.subject, "Subject without common name in openssl format, e.g. /O=Acme/OU=Platform/C=DE.")
+	cmd.Flags().StringVar(&o.keyTypeName, "key-type", o.keyTypeName, "Type of the private key: rsa2048, rsa4096, ecdsa-p256, ecdsa-p384 or ed25519. Defaults to rsa4096.")
+	cmd.Flags().BoolVarP(&o.silent, "silent", "s", o.silent, "Silent mode, omits output")
+
+	return cmd
+}
@@ -0,0 +1,63 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package server
+
+import (
+	"testing"
+)
+
+func TestComplete(t *testing.T) {
+	ts := []struct {
+		args    []string
+		ips     []string
+		wantdir string
+		wanterr bool
+	}{
+		{args: []string{"my.domain.com"}, ips: []string{"10.0.0.1", "::1"}, wantdir: "pki"},
+		{args: []string{"my.domain.com"}, ips: []string{"invalid ip"}, wanterr: true},
+		{args: []string{}, wanterr: true},
+		{args: []string{"a.com", "b.com"}, wanterr: true},
+	}
+
+	for _, tt := range ts {
+		o := &option{caDirectory: "pki", ipAddresses: tt.ips}
+		err := o.Complete(tt.args)
+		if tt.wanterr != (err != nil) {
+			t.Fatalf("%v: want error %v, got %v", tt.args, tt.wanterr, err)
+		}
+		if tt.wanterr {
+			continue
+		}
+		if o.directory != tt.wantdir || len(o.ips) != len(tt.ips) {
+			t.Fatalf("%v: want dir %q and %d ips, got %q and %v", tt.args, tt.wantdir, len(tt.ips), o.directory, o.ips)
+		}
+	}
+}
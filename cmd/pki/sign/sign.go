@@ -0,0 +1,196 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package sign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	aeCMD "github.com/aurae-runtime/ae/cmd"
+	"github.com/aurae-runtime/ae/pkg/cli"
+	"github.com/aurae-runtime/ae/pkg/cli/printer"
+	"github.com/aurae-runtime/ae/pkg/pki"
+	"github.com/spf13/cobra"
+)
+
+type option struct {
+	aeCMD.Option
+	outputFormat *cli.OutputFormat
+	caDirectory  string
+	directory    string
+	csrFile      string
+	user         string
+	days         uint
+	daysSet      bool
+	notAfter     string
+	templateFile string
+	profile      pki.Profile
+	silent       bool
+	writer       io.Writer
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("command 'sign' requires a certificate request as argument")
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("too many arguments for command 'sign', expect %d, got %d", 1, len(args))
+	}
+
+	o.csrFile = args[0]
+	if o.user == "" {
+		o.user = userFromFile(o.csrFile)
+	}
+	if o.directory == "" {
+		o.directory = filepath.Dir(o.csrFile)
+	}
+
+	o.profile = pki.Profile{}
+	if o.templateFile != "" {
+		template, err := pki.LoadTemplate(o.templateFile)
+		if err != nil {
+			return err
+		}
+		o.profile = template.Client
+	}
+	if o.daysSet {
+		o.profile.Validity = pki.Duration(time.Duration(o.days) * 24 * time.Hour)
+		o.profile.NotAfter = time.Time{}
+	}
+	if o.notAfter != "" {
+		notAfter, err := pki.ParseNotAfter(o.notAfter)
+		if err != nil {
+			return err
+		}
+		o.profile.NotAfter = notAfter
+		o.profile.Validity = 0
+	}
+
+	return nil
+}
+
+func (o *option) Validate() error {
+	if err := o.outputFormat.Validate(); err != nil {
+		return err
+	}
+	if o.caDirectory == "" {
+		return errors.New("--ca-dir must be passed to this command")
+	}
+	if o.user == "" {
+		return fmt.Errorf("could not derive user from %q, use --user", o.csrFile)
+	}
+	if o.days == 0 {
+		return errors.New("--days must be at least 1")
+	}
+	if o.daysSet && o.notAfter != "" {
+		return errors.New("--days and --not-after are mutually exclusive")
+	}
+	return nil
+}
+
+func (o *option) Execute(_ context.Context) error {
+	ca, err := pki.LoadCA(o.caDirectory)
+	if err != nil {
+		return fmt.Errorf("failed to load CA: %w", err)
+	}
+
+	csr, err := pki.ReadClientCSR(o.csrFile, o.user)
+	if err != nil {
+		return fmt.Errorf("failed to load client csr: %w", err)
+	}
+
+	crt, err := pki.HandleSignClientCSR(o.directory, ca, csr, o.profile)
+	if err != nil {
+		return fmt.Errorf("failed to sign client csr: %w", err)
+	}
+	if !o.silent {
+		o.outputFormat.ToPrinter().Print(o.writer, crt)
+	}
+	return nil
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+// userFromFile derives the user from a certificate request named
+// client.<user>.csr, as written by 'ae pki create --user'.
+func userFromFile(file string) string {
+	name := filepath.Base(file)
+	if !strings.HasPrefix(name, "client.") || !strings.HasSuffix(name, ".csr") {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(name, "client."), ".csr")
+}
+
+func NewCMD(ctx context.Context) *cobra.Command {
+	o := &option{
+		outputFormat: cli.NewOutputFormat().
+			WithDefaultFormat(printer.NewJSON().Format()).
+			WithPrinter(printer.NewJSON()).
+			WithPrinter(printer.NewYAML()),
+		days:   uint(pki.DefaultClientValidity / (24 * time.Hour)),
+		silent: false,
+	}
+	cmd := &cobra.Command{
+		Use:   "sign",
+		Short: "Signs a client certificate request with the CA.",
+		Long: `Signs a client certificate request with the CA.
+
+The CA is loaded from ca.crt and ca.key in the CA directory. The signed
+certificate is written as _signed.client.<user>.crt next to the certificate
+request, where the user is taken from the request file name
+client.<user>.csr unless --user is passed.`,
+		Example: `ae pki sign --ca-dir ./pki/ ./pki/client.nova.csr
+ae pki sign --ca-dir ./pki/ --user nova --days 30 --dir ~/.aurae/pki/ nova.csr`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.daysSet = cmd.Flags().Changed("days")
+			return aeCMD.Run(ctx, o, cmd, args)
+		},
+	}
+
+	o.outputFormat.AddFlags(cmd)
+	cmd.Flags().StringVar(&o.caDirectory, "ca-dir", o.caDirectory, "Directory containing ca.crt and ca.key.")
+	cmd.Flags().StringVarP(&o.directory, "dir", "d", o.directory, "Output directory to store the signed certificate. Defaults to the directory of the certificate request.")
+	cmd.Flags().StringVarP(&o.user, "user", "u", o.user, "User the certificate is issued for.")
+	cmd.Flags().UintVar(&o.days, "days", o.days, "Number of days the certificate is valid, limited by the validity of the CA.")
+	cmd.Flags().StringVar(&o.notAfter, "not-after", o.notAfter, "Date until the certificate is valid, as YYYY-MM-DD or RFC 3339 timestamp.")
+	cmd.Flags().StringVar(&o.templateFile, "template", o.templateFile, "YAML template whose client profile configures the certificate. Flags take precedence.")
+	cmd.Flags().BoolVarP(&o.silent, "silent", "s", o.silent, "Silent mode, omits output")
+
+	return cmd
+}
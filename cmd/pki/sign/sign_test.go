@@ -0,0 +1,90 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package sign
+
+import (
+	"testing"
+
+	"github.com/aurae-runtime/ae/pkg/cli"
+	"github.com/aurae-runtime/ae/pkg/cli/printer"
+)
+
+func TestComplete(t *testing.T) {
+	ts := []struct {
+		args     []string
+		user     string
+		wantuser string
+		wantdir  string
+		wanterr  bool
+	}{
+		{args: []string{"./pki/client.nova.csr"}, wantuser: "nova", wantdir: "pki"},
+		{args: []string{"nova.csr"}, wantuser: "", wantdir: "."},
+		{args: []string{"nova.csr"}, user: "christoph", wantuser: "christoph", wantdir: "."},
+		{args: []string{}, wanterr: true},
+		{args: []string{"a.csr", "b.csr"}, wanterr: true},
+	}
+
+	for _, tt := range ts {
+		o := &option{user: tt.user}
+		err := o.Complete(tt.args)
+		if tt.wanterr != (err != nil) {
+			t.Fatalf("%v: want error %v, got %v", tt.args, tt.wanterr, err)
+		}
+		if o.user != tt.wantuser || o.directory != tt.wantdir {
+			t.Fatalf("%v: want user %q and dir %q, got %q and %q", tt.args, tt.wantuser, tt.wantdir, o.user, o.directory)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	outputFormat := cli.NewOutputFormat().WithDefaultFormat("json").WithPrinter(printer.NewJSON())
+	ts := []struct {
+		name    string
+		o       option
+		wanterr bool
+	}{
+		{name: "valid", o: option{caDirectory: "pki", user: "nova", days: 1}},
+		{name: "no ca dir", o: option{user: "nova", days: 1}, wanterr: true},
+		{name: "no user", o: option{caDirectory: "pki", days: 1}, wanterr: true},
+		{name: "no validity", o: option{caDirectory: "pki", user: "nova"}, wanterr: true},
+	}
+
+	for _, tt := range ts {
+		tt.o.outputFormat = outputFormat
+		goterr := tt.o.Validate()
+		if tt.wanterr && goterr == nil {
+			t.Fatalf("[%s] want error, got no error", tt.name)
+		}
+		if !tt.wanterr && goterr != nil {
+			t.Fatalf("[%s] want no error, got error: %s", tt.name, goterr)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	aeCMD "github.com/aurae-runtime/ae/cmd"
+	"github.com/aurae-runtime/ae/pkg/cli"
+	"github.com/aurae-runtime/ae/pkg/cli/printer"
+	"github.com/aurae-runtime/ae/pkg/pki"
+	"github.com/spf13/cobra"
+)
+
+type option struct {
+	aeCMD.Option
+	outputFormat *cli.OutputFormat
+	directory    string
+	writer       io.Writer
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) == 0 {
+		return errors.New("command 'verify' requires a pki directory as argument")
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("too many arguments for command 'verify', expect %d, got %d", 1, len(args))
+	}
+
+	o.directory = args[0]
+	return nil
+}
+
+func (o *option) Validate() error {
+	return o.outputFormat.Validate()
+}
+
+func (o *option) Execute(_ context.Context) error {
+	report, err := pki.VerifyDirectory(o.directory)
+	if err != nil {
+		return err
+	}
+	if err := o.outputFormat.ToPrinter().Print(o.writer, report); err != nil {
+		return err
+	}
+	if !report.OK() {
+		return fmt.Errorf("pki in %s is invalid", o.directory)
+	}
+	return nil
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func NewCMD(ctx context.Context) *cobra.Command {
+	o := &option{
+		outputFormat: cli.NewOutputFormat().
+			WithDefaultFormat(printer.NewText().Format()).
+			WithPrinter(printer.NewText()).
+			WithPrinter(printer.NewJSON()).
+			WithPrinter(printer.NewYAML()),
+	}
+	cmd := &cobra.Command{
+		Use:   "verify [dir]",
+		Short: "Verifies the PKI in a directory.",
+		Long: `Verifies the PKI in a directory as created by 'ae pki init'.
+
+The CA must be self-signed or followed by the chain that issued it, and match
+ca.key if the directory holds it, as client directories do not. The server
+certificate and the signed client certificates must be issued by the CA, be
+valid for server and client authentication respectively, and match their
+private keys. Client certificate requests must match the keys of their users.
+
+The command fails if any file is invalid.`,
+		Example: `ae pki verify ~/.aurae/pki/`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return aeCMD.Run(ctx, o, cmd, args)
+		},
+	}
+
+	o.outputFormat.AddFlags(cmd)
+
+	return cmd
+}
@@ -33,13 +33,17 @@ package root_cmd
 import (
 	"context"
 	"os"
+	"path/filepath"
 
+	"github.com/aurae-runtime/ae/cmd/call"
+	"github.com/aurae-runtime/ae/cmd/cri"
 	"github.com/aurae-runtime/ae/cmd/discovery"
 	"github.com/aurae-runtime/ae/cmd/health"
 	"github.com/aurae-runtime/ae/cmd/observe"
 	"github.com/aurae-runtime/ae/cmd/oci"
 	"github.com/aurae-runtime/ae/cmd/pki"
 	"github.com/aurae-runtime/ae/cmd/version"
+	"github.com/aurae-runtime/ae/cmd/vms"
 	"github.com/spf13/cobra"
 )
 
@@ -52,6 +56,11 @@ var rootCmd = &cobra.Command{
 }
 
 func Execute() {
+	// Invoked through a link named ae-oci, ae acts as an OCI runtime binary
+	// that container managers call the same way as runc.
+	if filepath.Base(os.Args[0]) == "ae-oci" {
+		rootCmd.SetArgs(append([]string{"oci"}, os.Args[1:]...))
+	}
 	err := rootCmd.Execute()
 	if err != nil {
 		os.Exit(1)
@@ -61,10 +70,13 @@ func Execute() {
 func init() {
 	// add subcommands
 	ctx := context.Background()
+	rootCmd.AddCommand(call.NewCMD(ctx))
+	rootCmd.AddCommand(cri.NewCMD(ctx))
 	rootCmd.AddCommand(discovery.NewCMD(ctx))
 	rootCmd.AddCommand(health.NewCMD(ctx))
 	rootCmd.AddCommand(observe.NewCMD(ctx))
 	rootCmd.AddCommand(oci.NewCMD(ctx))
 	rootCmd.AddCommand(pki.NewCMD(ctx))
 	rootCmd.AddCommand(version.NewCMD(ctx))
+	rootCmd.AddCommand(vms.NewCMD(ctx))
 }
@@ -0,0 +1,163 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package allocate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	aeCMD "github.com/aurae-runtime/ae/cmd"
+	"github.com/aurae-runtime/ae/pkg/cli"
+	"github.com/aurae-runtime/ae/pkg/cli/printer"
+	"github.com/aurae-runtime/ae/pkg/client"
+	"github.com/aurae-runtime/ae/pkg/config"
+	"github.com/aurae-runtime/ae/pkg/vms"
+	"github.com/spf13/cobra"
+
+	vmsv0 "github.com/aurae-runtime/ae/pkg/api/v0/vms"
+)
+
+type option struct {
+	aeCMD.Option
+	auth         *config.Auth
+	outputFormat *cli.OutputFormat
+	id           string
+	kernel       string
+	kernelArgs   string
+	rootDrive    string
+	rootDriveRO  bool
+	vcpus        uint32
+	memory       uint32
+	writer       io.Writer
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) != 1 {
+		return errors.New("expected vm id to be passed to this command")
+	}
+	o.id = args[0]
+	return nil
+}
+
+func (o *option) Validate() error {
+	if err := o.outputFormat.Validate(); err != nil {
+		return err
+	}
+	if len(o.id) == 0 {
+		return errors.New("vm id must not be empty")
+	}
+	if len(o.kernel) == 0 {
+		return errors.New("--kernel must be passed to this command")
+	}
+	if len(o.rootDrive) == 0 {
+		return errors.New("--root-drive must be passed to this command")
+	}
+	if o.vcpus == 0 {
+		return errors.New("--vcpus must be at least 1")
+	}
+	if o.memory == 0 {
+		return errors.New("--memory must be at least 1 MiB")
+	}
+	return nil
+}
+
+func (o *option) Execute(ctx context.Context) error {
+	c, err := client.New(ctx, config.WithAuth(*o.auth))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	v, err := c.VMs()
+	if err != nil {
+		return err
+	}
+
+	rsp, err := v.Allocate(ctx, &vmsv0.VmServiceAllocateRequest{Machine: o.machine()})
+	if err != nil {
+		return fmt.Errorf("failed to allocate vm %q: %w", o.id, err)
+	}
+	return o.outputFormat.ToPrinter().Print(o.writer, vms.Result{ID: rsp.VmId})
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func (o *option) machine() *vmsv0.VirtualMachine {
+	return &vmsv0.VirtualMachine{
+		Id:            o.id,
+		MemSizeMb:     o.memory,
+		VcpuCount:     o.vcpus,
+		KernelImgPath: o.kernel,
+		KernelArgs:    strings.Fields(o.kernelArgs),
+		RootDrive: &vmsv0.RootDrive{
+			ImagePath: o.rootDrive,
+			ReadOnly:  o.rootDriveRO,
+		},
+	}
+}
+
+func NewCMD(ctx context.Context) *cobra.Command {
+	o := &option{
+		auth:   &config.Auth{},
+		vcpus:  1,
+		memory: 512,
+		outputFormat: cli.NewOutputFormat().
+			WithDefaultFormat(printer.NewText().Format()).
+			WithPrinter(printer.NewText()).
+			WithPrinter(printer.NewJSON()).
+			WithPrinter(printer.NewYAML()),
+	}
+	cmd := &cobra.Command{
+		Use:   "allocate <vm-id>",
+		Short: "Allocate a virtual machine without starting it.",
+		Long: `Allocate a virtual machine without starting it.
+
+The kernel image and root drive are paths on the node running the virtual
+machine.`,
+		Example: `ae vms allocate web --kernel /var/lib/aurae/vmlinux.bin --root-drive /var/lib/aurae/rootfs.ext4 --vcpus 2 --memory 1024`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return aeCMD.Run(ctx, o, cmd, args)
+		},
+	}
+	o.auth.AddFlags(cmd)
+	o.outputFormat.AddFlags(cmd)
+	cmd.Flags().StringVar(&o.kernel, "kernel", o.kernel, "Path of the kernel image to boot")
+	cmd.Flags().StringVar(&o.kernelArgs, "kernel-args", o.kernelArgs, "Command line of the kernel, e.g. \"console=ttyS0 reboot=k\"")
+	cmd.Flags().StringVar(&o.rootDrive, "root-drive", o.rootDrive, "Path of the image used as root drive")
+	cmd.Flags().BoolVar(&o.rootDriveRO, "root-drive-read-only", o.rootDriveRO, "Attach the root drive read-only")
+	cmd.Flags().Uint32Var(&o.vcpus, "vcpus", o.vcpus, "Number of virtual CPUs")
+	cmd.Flags().Uint32Var(&o.memory, "memory", o.memory, "Memory size in MiB")
+	return cmd
+}
@@ -0,0 +1,69 @@
+package allocate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aurae-runtime/ae/pkg/cli"
+	"github.com/aurae-runtime/ae/pkg/cli/printer"
+)
+
+func TestValidate(t *testing.T) {
+	valid := func() *option {
+		return &option{
+			outputFormat: cli.NewOutputFormat().WithDefaultFormat("text").WithPrinter(printer.NewText()),
+			id:           "web",
+			kernel:       "/var/lib/aurae/vmlinux.bin",
+			rootDrive:    "/var/lib/aurae/rootfs.ext4",
+			vcpus:        1,
+			memory:       512,
+		}
+	}
+
+	ts := []struct {
+		name    string
+		modify  func(o *option)
+		wanterr bool
+	}{
+		{name: "valid", modify: func(o *option) {}},
+		{name: "no id", modify: func(o *option) { o.id = "" }, wanterr: true},
+		{name: "no kernel", modify: func(o *option) { o.kernel = "" }, wanterr: true},
+		{name: "no root drive", modify: func(o *option) { o.rootDrive = "" }, wanterr: true},
+		{name: "no vcpus", modify: func(o *option) { o.vcpus = 0 }, wanterr: true},
+		{name: "no memory", modify: func(o *option) { o.memory = 0 }, wanterr: true},
+	}
+
+	for _, tt := range ts {
+		o := valid()
+		tt.modify(o)
+		goterr := o.Validate()
+		if tt.wanterr && goterr == nil {
+			t.Fatalf("[%s] want error, got no error", tt.name)
+		}
+		if !tt.wanterr && goterr != nil {
+			t.Fatalf("[%s] want no error, got error: %s", tt.name, goterr)
+		}
+	}
+}
+
+func TestMachine(t *testing.T) {
+	o := &option{
+		id:          "web",
+		kernel:      "/vmlinux",
+		kernelArgs:  " console=ttyS0  reboot=k ",
+		rootDrive:   "/rootfs.ext4",
+		rootDriveRO: true,
+		vcpus:       2,
+		memory:      1024,
+	}
+	m := o.machine()
+	if m.Id != "web" || m.VcpuCount != 2 || m.MemSizeMb != 1024 || m.KernelImgPath != "/vmlinux" {
+		t.Fatalf("unexpected machine %+v", m)
+	}
+	if want := []string{"console=ttyS0", "reboot=k"}; !reflect.DeepEqual(m.KernelArgs, want) {
+		t.Fatalf("want kernel args %q, got %q", want, m.KernelArgs)
+	}
+	if m.RootDrive.ImagePath != "/rootfs.ext4" || !m.RootDrive.ReadOnly {
+		t.Fatalf("unexpected root drive %+v", m.RootDrive)
+	}
+}
@@ -0,0 +1,90 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package vms
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	aeCMD "github.com/aurae-runtime/ae/cmd"
+	"github.com/aurae-runtime/ae/cmd/vms/allocate"
+	"github.com/aurae-runtime/ae/cmd/vms/free"
+	"github.com/aurae-runtime/ae/cmd/vms/list"
+	"github.com/aurae-runtime/ae/cmd/vms/start"
+	"github.com/aurae-runtime/ae/cmd/vms/stop"
+	"github.com/spf13/cobra"
+)
+
+type option struct {
+	aeCMD.Option
+	writer io.Writer
+}
+
+func (o *option) Complete(_ []string) error {
+	return nil
+}
+
+func (o *option) Validate() error {
+	return nil
+}
+
+func (o *option) Execute(_ context.Context) error {
+	fmt.Fprintln(o.writer, "vms called")
+	return nil
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func NewCMD(ctx context.Context) *cobra.Command {
+	o := &option{}
+	cmd := &cobra.Command{
+		Use:   "vms",
+		Short: "CLI for aurae vms service.",
+		Long: `CLI for aurae vms service.
+
+Virtual machines are managed the same way as cells: they are allocated with
+their kernel, root drive, vCPUs and memory, started, stopped and freed.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return aeCMD.Run(ctx, o, cmd, args)
+		},
+	}
+
+	cmd.AddCommand(allocate.NewCMD(ctx))
+	cmd.AddCommand(free.NewCMD(ctx))
+	cmd.AddCommand(list.NewCMD(ctx))
+	cmd.AddCommand(start.NewCMD(ctx))
+	cmd.AddCommand(stop.NewCMD(ctx))
+
+	return cmd
+}
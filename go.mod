@@ -4,6 +4,9 @@ go 1.20
 
 require (
 	github.com/3th1nk/cidr v0.2.0
+	github.com/opencontainers/go-digest v1.0.0
+	github.com/opencontainers/image-spec v1.1.0
+	github.com/opencontainers/runtime-spec v1.2.0
 	github.com/prometheus/common v0.55.0
 	github.com/spf13/cobra v1.8.1
 	google.golang.org/grpc v1.64.1
@@ -0,0 +1,280 @@
+package call
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+)
+
+// Call invokes arbitrary gRPC methods using JSON encoded messages. Methods
+// are resolved through server reflection, falling back to the descriptors
+// compiled into this binary.
+type Call interface {
+	Resolve(ctx context.Context, method string) (protoreflect.MethodDescriptor, error)
+	Invoke(ctx context.Context, md protoreflect.MethodDescriptor, body io.Reader, handler func(proto.Message) error) error
+}
+
+type call struct {
+	conn grpc.ClientConnInterface
+}
+
+func New(ctx context.Context, conn grpc.ClientConnInterface) Call {
+	return &call{
+		conn: conn,
+	}
+}
+
+// SplitMethod splits a method in the form "package.Service/Method" or
+// "package.Service.Method" into its service and method names.
+func SplitMethod(method string) (string, string, error) {
+	method = strings.TrimPrefix(method, "/")
+	sep := strings.LastIndex(method, "/")
+	if sep < 0 {
+		sep = strings.LastIndex(method, ".")
+	}
+	if sep <= 0 || sep == len(method)-1 {
+		return "", "", fmt.Errorf("invalid method %q, expected <package.Service>/<Method>", method)
+	}
+	return method[:sep], method[sep+1:], nil
+}
+
+func (c *call) Resolve(ctx context.Context, method string) (protoreflect.MethodDescriptor, error) {
+	svc, name, err := SplitMethod(method)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := c.reflect(ctx, svc)
+	if err != nil {
+		if code := status.Code(err); code != codes.Unimplemented && code != codes.NotFound {
+			return nil, fmt.Errorf("failed to resolve %q through server reflection: %w", svc, err)
+		}
+		files = protoregistry.GlobalFiles
+	}
+
+	d, err := files.FindDescriptorByName(protoreflect.FullName(svc))
+	if err != nil {
+		return nil, fmt.Errorf("unknown service %q: %w", svc, err)
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a service", svc)
+	}
+	md := sd.Methods().ByName(protoreflect.Name(name))
+	if md == nil {
+		return nil, fmt.Errorf("service %q has no method %q", svc, name)
+	}
+	return md, nil
+}
+
+func (c *call) Invoke(ctx context.Context, md protoreflect.MethodDescriptor, body io.Reader, handler func(proto.Message) error) error {
+	fullMethod := fmt.Sprintf("/%s/%s", md.Parent().FullName(), md.Name())
+
+	reqs, err := decodeRequests(md.Input(), body)
+	if err != nil {
+		return err
+	}
+
+	if !md.IsStreamingClient() && !md.IsStreamingServer() {
+		if len(reqs) != 1 {
+			return fmt.Errorf("method %q expects exactly one request message, got %d", fullMethod, len(reqs))
+		}
+		rsp := dynamicpb.NewMessage(md.Output())
+		if err := c.conn.Invoke(ctx, fullMethod, reqs[0], rsp); err != nil {
+			return err
+		}
+		return handler(rsp)
+	}
+
+	if !md.IsStreamingClient() && len(reqs) != 1 {
+		return fmt.Errorf("method %q expects exactly one request message, got %d", fullMethod, len(reqs))
+	}
+
+	desc := &grpc.StreamDesc{
+		StreamName:    string(md.Name()),
+		ServerStreams: md.IsStreamingServer(),
+		ClientStreams: md.IsStreamingClient(),
+	}
+	stream, err := c.conn.NewStream(ctx, desc, fullMethod)
+	if err != nil {
+		return err
+	}
+	for _, req := range reqs {
+		if err := stream.SendMsg(req); err != nil {
+			return err
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+
+	for {
+		rsp := dynamicpb.NewMessage(md.Output())
+		err := stream.RecvMsg(rsp)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := handler(rsp); err != nil {
+			return err
+		}
+	}
+}
+
+// decodeRequests reads a sequence of JSON objects from body. An empty body is
+// treated as a single empty request message.
+func decodeRequests(input protoreflect.MessageDescriptor, body io.Reader) ([]proto.Message, error) {
+	var reqs []proto.Message
+	if body != nil {
+		dec := json.NewDecoder(body)
+		for {
+			var raw json.RawMessage
+			err := dec.Decode(&raw)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read request body: %w", err)
+			}
+			req := dynamicpb.NewMessage(input)
+			if err := protojson.Unmarshal(raw, req); err != nil {
+				return nil, fmt.Errorf("failed to parse request as %s: %w", input.FullName(), err)
+			}
+			reqs = append(reqs, req)
+		}
+	}
+	if len(reqs) == 0 {
+		reqs = append(reqs, dynamicpb.NewMessage(input))
+	}
+	return reqs, nil
+}
+
+// reflect fetches the file descriptor declaring the given symbol, including
+// its transitive dependencies, from the server reflection service.
+func (c *call) reflect(ctx context.Context, symbol string) (*protoregistry.Files, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := reflectionv1.NewServerReflectionClient(c.conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fdps := make(map[string]*descriptorpb.FileDescriptorProto)
+	fetch := func(req *reflectionv1.ServerReflectionRequest) ([]*descriptorpb.FileDescriptorProto, error) {
+		if err := stream.Send(req); err != nil {
+			return nil, err
+		}
+		rsp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if e := rsp.GetErrorResponse(); e != nil {
+			return nil, status.Error(codes.Code(e.ErrorCode), e.ErrorMessage)
+		}
+		var out []*descriptorpb.FileDescriptorProto
+		for _, b := range rsp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			fdp := &descriptorpb.FileDescriptorProto{}
+			if err := proto.Unmarshal(b, fdp); err != nil {
+				return nil, fmt.Errorf("failed to parse file descriptor: %w", err)
+			}
+			fdps[fdp.GetName()] = fdp
+			out = append(out, fdp)
+		}
+		return out, nil
+	}
+
+	pending, err := fetch(&reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: symbol},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for len(pending) > 0 {
+		fdp := pending[0]
+		pending = pending[1:]
+		for _, dep := range fdp.GetDependency() {
+			if _, ok := fdps[dep]; ok {
+				continue
+			}
+			if _, err := protoregistry.GlobalFiles.FindFileByPath(dep); err == nil {
+				continue
+			}
+			fetched, err := fetch(&reflectionv1.ServerReflectionRequest{
+				MessageRequest: &reflectionv1.ServerReflectionRequest_FileByFilename{FileByFilename: dep},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch dependency %q: %w", dep, err)
+			}
+			pending = append(pending, fetched...)
+		}
+	}
+
+	files := &protoregistry.Files{}
+	var register func(name string) error
+	register = func(name string) error {
+		if _, err := files.FindFileByPath(name); err == nil {
+			return nil
+		}
+		fdp, ok := fdps[name]
+		if !ok {
+			return nil
+		}
+		for _, dep := range fdp.GetDependency() {
+			if err := register(dep); err != nil {
+				return err
+			}
+		}
+		fd, err := protodesc.NewFile(fdp, resolver{files})
+		if err != nil {
+			return fmt.Errorf("failed to build file descriptor %q: %w", name, err)
+		}
+		return files.RegisterFile(fd)
+	}
+	for name := range fdps {
+		if err := register(name); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// resolver looks up descriptors in the reflected files first and falls back
+// to the descriptors linked into this binary.
+type resolver struct {
+	files *protoregistry.Files
+}
+
+func (r resolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	fd, err := r.files.FindFileByPath(path)
+	if errors.Is(err, protoregistry.NotFound) {
+		return protoregistry.GlobalFiles.FindFileByPath(path)
+	}
+	return fd, err
+}
+
+func (r resolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	d, err := r.files.FindDescriptorByName(name)
+	if errors.Is(err, protoregistry.NotFound) {
+		return protoregistry.GlobalFiles.FindDescriptorByName(name)
+	}
+	return d, err
+}
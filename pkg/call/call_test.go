@@ -0,0 +1,126 @@
+package call
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	healthv1 "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func newTestCall(t *testing.T, withReflection bool) Call {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	healthv1.RegisterHealthServer(srv, health.NewServer())
+	if withReflection {
+		reflection.Register(srv)
+	}
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}))
+	if err != nil {
+		t.Fatalf("failed to dial test server: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return New(context.Background(), conn)
+}
+
+func TestSplitMethod(t *testing.T) {
+	ts := []struct {
+		method      string
+		wantservice string
+		wantmethod  string
+		wanterr     bool
+	}{
+		{"aurae.cells.v0.CellService/List", "aurae.cells.v0.CellService", "List", false},
+		{"/aurae.cells.v0.CellService/List", "aurae.cells.v0.CellService", "List", false},
+		{"aurae.cells.v0.CellService.List", "aurae.cells.v0.CellService", "List", false},
+		{"List", "", "", true},
+		{"aurae.cells.v0.CellService/", "", "", true},
+	}
+
+	for _, tt := range ts {
+		service, method, err := SplitMethod(tt.method)
+		if tt.wanterr && err == nil {
+			t.Fatalf("[%s] want error, got no error", tt.method)
+		}
+		if !tt.wanterr && err != nil {
+			t.Fatalf("[%s] want no error, got error: %s", tt.method, err)
+		}
+		if service != tt.wantservice || method != tt.wantmethod {
+			t.Fatalf("[%s] want %q %q, got %q %q", tt.method, tt.wantservice, tt.wantmethod, service, method)
+		}
+	}
+}
+
+func TestResolveAndInvoke(t *testing.T) {
+	for _, withReflection := range []bool{true, false} {
+		c := newTestCall(t, withReflection)
+
+		md, err := c.Resolve(context.Background(), "grpc.health.v1.Health/Check")
+		if err != nil {
+			t.Fatalf("[reflection=%t] failed to resolve method: %s", withReflection, err)
+		}
+
+		var got []string
+		err = c.Invoke(context.Background(), md, strings.NewReader(`{"service": ""}`), func(rsp proto.Message) error {
+			b, err := protojson.Marshal(rsp)
+			got = append(got, string(b))
+			return err
+		})
+		if err != nil {
+			t.Fatalf("[reflection=%t] failed to invoke method: %s", withReflection, err)
+		}
+		if len(got) != 1 || !strings.Contains(got[0], "SERVING") {
+			t.Fatalf("[reflection=%t] unexpected response %v", withReflection, got)
+		}
+	}
+}
+
+func TestInvokeServerStream(t *testing.T) {
+	c := newTestCall(t, true)
+
+	md, err := c.Resolve(context.Background(), "grpc.health.v1.Health/Watch")
+	if err != nil {
+		t.Fatalf("failed to resolve method: %s", err)
+	}
+	if !md.IsStreamingServer() {
+		t.Fatal("want server streaming method")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err = c.Invoke(ctx, md, nil, func(rsp proto.Message) error {
+		cancel()
+		return nil
+	})
+	if err == nil {
+		t.Fatal("want error from canceled stream, got no error")
+	}
+}
+
+func TestResolveUnknown(t *testing.T) {
+	c := newTestCall(t, true)
+
+	if _, err := c.Resolve(context.Background(), "grpc.health.v1.Health/Unknown"); err == nil {
+		t.Fatal("want error for unknown method, got no error")
+	}
+	if _, err := c.Resolve(context.Background(), "aurae.unknown.v0.Unknown/List"); err == nil {
+		t.Fatal("want error for unknown service, got no error")
+	}
+}
@@ -0,0 +1,47 @@
+package cells
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	cellsv0 "github.com/aurae-runtime/ae/pkg/api/v0/cells"
+)
+
+type Cells interface {
+	Allocate(context.Context, *cellsv0.CellServiceAllocateRequest) (*cellsv0.CellServiceAllocateResponse, error)
+	Free(context.Context, *cellsv0.CellServiceFreeRequest) (*cellsv0.CellServiceFreeResponse, error)
+	Start(context.Context, *cellsv0.CellServiceStartRequest) (*cellsv0.CellServiceStartResponse, error)
+	Stop(context.Context, *cellsv0.CellServiceStopRequest) (*cellsv0.CellServiceStopResponse, error)
+	List(context.Context, *cellsv0.CellServiceListRequest) (*cellsv0.CellServiceListResponse, error)
+}
+
+type cells struct {
+	client cellsv0.CellServiceClient
+}
+
+func New(ctx context.Context, conn grpc.ClientConnInterface) Cells {
+	return &cells{
+		client: cellsv0.NewCellServiceClient(conn),
+	}
+}
+
+func (c *cells) Allocate(ctx context.Context, req *cellsv0.CellServiceAllocateRequest) (*cellsv0.CellServiceAllocateResponse, error) {
+	return c.client.Allocate(ctx, req)
+}
+
+func (c *cells) Free(ctx context.Context, req *cellsv0.CellServiceFreeRequest) (*cellsv0.CellServiceFreeResponse, error) {
+	return c.client.Free(ctx, req)
+}
+
+func (c *cells) Start(ctx context.Context, req *cellsv0.CellServiceStartRequest) (*cellsv0.CellServiceStartResponse, error) {
+	return c.client.Start(ctx, req)
+}
+
+func (c *cells) Stop(ctx context.Context, req *cellsv0.CellServiceStopRequest) (*cellsv0.CellServiceStopResponse, error) {
+	return c.client.Stop(ctx, req)
+}
+
+func (c *cells) List(ctx context.Context, req *cellsv0.CellServiceListRequest) (*cellsv0.CellServiceListResponse, error) {
+	return c.client.List(ctx, req)
+}
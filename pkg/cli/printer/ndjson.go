@@ -0,0 +1,32 @@
+package printer
+
+import (
+	"encoding/json"
+	"io"
+)
+
+var _ Interface = NewNDJSON()
+
+// NDJSON prints every object as a single line of JSON, which is suited for
+// streams of objects.
+type NDJSON struct {
+}
+
+func NewNDJSON() *NDJSON {
+	return &NDJSON{}
+}
+
+func (printer *NDJSON) Format() string {
+	return "ndjson"
+}
+
+func (printer *NDJSON) Print(w io.Writer, obj any) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	data = append(data, '\n')
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,26 @@
+package printer
+
+import (
+	"fmt"
+	"io"
+)
+
+var _ Interface = NewText()
+
+// Text prints objects using their default format, which makes use of the
+// String method for types implementing fmt.Stringer.
+type Text struct {
+}
+
+func NewText() *Text {
+	return &Text{}
+}
+
+func (printer *Text) Format() string {
+	return "text"
+}
+
+func (printer *Text) Print(w io.Writer, obj any) error {
+	_, err := fmt.Fprintln(w, obj)
+	return err
+}
@@ -1,9 +1,11 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"net"
 	"os"
@@ -11,24 +13,36 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 
+	"github.com/aurae-runtime/ae/pkg/call"
+	"github.com/aurae-runtime/ae/pkg/cells"
 	"github.com/aurae-runtime/ae/pkg/config"
+	"github.com/aurae-runtime/ae/pkg/cri"
 	"github.com/aurae-runtime/ae/pkg/discovery"
 	"github.com/aurae-runtime/ae/pkg/health"
 	"github.com/aurae-runtime/ae/pkg/observe"
+	"github.com/aurae-runtime/ae/pkg/vms"
 )
 
 type Client interface {
+	Call() (call.Call, error)
+	Cells() (cells.Cells, error)
+	CRI() (cri.CRI, error)
 	Discovery() (discovery.Discovery, error)
 	Health() (health.Health, error)
 	Observe() (observe.Observe, error)
+	VMs() (vms.VMs, error)
 }
 
 type client struct {
 	cfg       *config.Configs
 	conn      grpc.ClientConnInterface
+	call      call.Call
+	cells     cells.Cells
+	cri       cri.CRI
 	discovery discovery.Discovery
 	health    health.Health
 	observe   observe.Observe
+	vms       vms.VMs
 }
 
 func New(ctx context.Context, cfg ...config.Config) (Client, error) {
@@ -55,9 +69,13 @@ func New(ctx context.Context, cfg ...config.Config) (Client, error) {
 	return &client{
 		cfg:       cf,
 		conn:      conn,
+		call:      call.New(ctx, conn),
+		cells:     cells.New(ctx, conn),
+		cri:       cri.New(ctx, conn),
 		discovery: discovery.New(ctx, conn),
 		health:    health.New(ctx, conn),
 		observe:   observe.New(ctx, conn),
+		vms:       vms.New(ctx, conn),
 	}, nil
 }
 
@@ -72,7 +90,7 @@ func loadTLSCredentials(auth config.Auth) (credentials.TransportCredentials, err
 		return nil, fmt.Errorf("failed to add server CA's certificate")
 	}
 
-	clientKeyPair, err := tls.LoadX509KeyPair(auth.ClientCert, auth.ClientKey)
+	clientKeyPair, err := loadClientCertificate(auth.ClientCert, auth.ClientKey)
 	if err != nil {
 		return nil, err
 	}
@@ -86,6 +104,83 @@ func loadTLSCredentials(auth config.Auth) (credentials.TransportCredentials, err
 	return credentials.NewTLS(config), nil
 }
 
+// loadClientCertificate loads the client certificate and key. The certificate
+// file may contain a chain, e.g. when issued by an intermediate CA, in any
+// order. The certificate matching the key is presented first, followed by
+// the intermediates; roots are left out as the server has to trust them
+// already.
+func loadClientCertificate(certFile, keyFile string) (tls.Certificate, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	var certs [][]byte
+	for rest := certPEM; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			certs = append(certs, block.Bytes)
+		}
+	}
+	if len(certs) == 0 {
+		return tls.Certificate{}, fmt.Errorf("no certificate found in %s", certFile)
+	}
+
+	for i, leaf := range certs {
+		pair, err := tls.X509KeyPair(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf}), keyPEM)
+		if err != nil {
+			continue
+		}
+		for j, der := range certs {
+			if j == i {
+				continue
+			}
+			crt, err := x509.ParseCertificate(der)
+			if err != nil {
+				return tls.Certificate{}, fmt.Errorf("failed to parse certificate in %s: %w", certFile, err)
+			}
+			if bytes.Equal(crt.RawSubject, crt.RawIssuer) {
+				continue
+			}
+			pair.Certificate = append(pair.Certificate, der)
+		}
+		return pair, nil
+	}
+
+	// report why the first certificate does not match
+	_, err = tls.X509KeyPair(certPEM, keyPEM)
+	return tls.Certificate{}, fmt.Errorf("no certificate in %s matches the key in %s: %w", certFile, keyFile, err)
+}
+
+func (c *client) Call() (call.Call, error) {
+	if c.call == nil {
+		return nil, fmt.Errorf("call service is not available")
+	}
+	return c.call, nil
+}
+
+func (c *client) Cells() (cells.Cells, error) {
+	if c.cells == nil {
+		return nil, fmt.Errorf("cells service is not available")
+	}
+	return c.cells, nil
+}
+
+func (c *client) CRI() (cri.CRI, error) {
+	if c.cri == nil {
+		return nil, fmt.Errorf("cri service is not available")
+	}
+	return c.cri, nil
+}
+
 func (c *client) Discovery() (discovery.Discovery, error) {
 	if c.discovery == nil {
 		return nil, fmt.Errorf("discovery service is not available")
@@ -106,3 +201,10 @@ func (c *client) Observe() (observe.Observe, error) {
 	}
 	return c.observe, nil
 }
+
+func (c *client) VMs() (vms.VMs, error) {
+	if c.vms == nil {
+		return nil, fmt.Errorf("vms service is not available")
+	}
+	return c.vms, nil
+}
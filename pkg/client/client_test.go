@@ -0,0 +1,95 @@
+package client
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aurae-runtime/ae/pkg/pki"
+)
+
+func TestLoadClientCertificate(t *testing.T) {
+	profile := pki.Profile{KeyType: pki.KeyTypeECDSAP256}
+	root, err := pki.HandleCreateAuraeRootCA("", "unsafe.aurae.io", profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := pki.HandleCreateIntermediateCA("", root, "cluster-1.unsafe.aurae.io", profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := pki.HandleCreateClientCSR("", "unsafe.aurae.io", "nova", profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crt, err := pki.HandleSignClientCSR("", ca, req, profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "client.nova.key")
+	if err := os.WriteFile(keyFile, []byte(req.PrivateKey), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := map[string]string{
+		"leaf and intermediate": crt.Certificate,
+		// the root is dropped and the leaf moved to the front
+		"root, intermediate and leaf": reverse(t, crt.Certificate+root.Certificate),
+	}
+	for name, bundle := range ts {
+		certFile := filepath.Join(dir, "_signed.client.nova.crt")
+		if err := os.WriteFile(certFile, []byte(bundle), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		pair, err := loadClientCertificate(certFile, keyFile)
+		if err != nil {
+			t.Fatalf("%s: could not load client certificate: %s", name, err)
+		}
+		if len(pair.Certificate) != 2 {
+			t.Fatalf("%s: want leaf and intermediate, got %d certificates", name, len(pair.Certificate))
+		}
+		leaf, err := x509.ParseCertificate(pair.Certificate[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if leaf.Subject.CommonName != "nova.unsafe.aurae.io" {
+			t.Fatalf("%s: want leaf first, got %s", name, leaf.Subject.CommonName)
+		}
+	}
+
+	other, err := pki.HandleCreateClientCSR("", "unsafe.aurae.io", "ops", profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, []byte(other.PrivateKey), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadClientCertificate(filepath.Join(dir, "_signed.client.nova.crt"), keyFile); err == nil {
+		t.Fatal("want error for key of another certificate, got no error")
+	}
+}
+
+func reverse(t *testing.T, bundle string) string {
+	t.Helper()
+	var blocks []*pem.Block
+	for rest := []byte(bundle); ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		blocks = append([]*pem.Block{block}, blocks...)
+	}
+	if len(blocks) != 3 {
+		t.Fatalf("want 3 certificates in bundle, got %d", len(blocks))
+	}
+	var out []byte
+	for _, block := range blocks {
+		out = append(out, pem.EncodeToMemory(block)...)
+	}
+	return string(out)
+}
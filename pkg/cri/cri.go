@@ -0,0 +1,54 @@
+package cri
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	criv1 "github.com/aurae-runtime/ae/pkg/api/cri/v1"
+)
+
+// CRI is the subset of the Kubernetes CRI RuntimeService implemented by
+// auraed that is used to inspect and debug pods.
+type CRI interface {
+	RunPodSandbox(context.Context, *criv1.RunPodSandboxRequest) (*criv1.RunPodSandboxResponse, error)
+	StopPodSandbox(context.Context, *criv1.StopPodSandboxRequest) (*criv1.StopPodSandboxResponse, error)
+	PodSandboxStatus(context.Context, *criv1.PodSandboxStatusRequest) (*criv1.PodSandboxStatusResponse, error)
+	ListPodSandbox(context.Context, *criv1.ListPodSandboxRequest) (*criv1.ListPodSandboxResponse, error)
+	ListContainers(context.Context, *criv1.ListContainersRequest) (*criv1.ListContainersResponse, error)
+	ContainerStatus(context.Context, *criv1.ContainerStatusRequest) (*criv1.ContainerStatusResponse, error)
+}
+
+type cri struct {
+	client criv1.RuntimeServiceClient
+}
+
+func New(ctx context.Context, conn grpc.ClientConnInterface) CRI {
+	return &cri{
+		client: criv1.NewRuntimeServiceClient(conn),
+	}
+}
+
+func (c *cri) RunPodSandbox(ctx context.Context, req *criv1.RunPodSandboxRequest) (*criv1.RunPodSandboxResponse, error) {
+	return c.client.RunPodSandbox(ctx, req)
+}
+
+func (c *cri) StopPodSandbox(ctx context.Context, req *criv1.StopPodSandboxRequest) (*criv1.StopPodSandboxResponse, error) {
+	return c.client.StopPodSandbox(ctx, req)
+}
+
+func (c *cri) PodSandboxStatus(ctx context.Context, req *criv1.PodSandboxStatusRequest) (*criv1.PodSandboxStatusResponse, error) {
+	return c.client.PodSandboxStatus(ctx, req)
+}
+
+func (c *cri) ListPodSandbox(ctx context.Context, req *criv1.ListPodSandboxRequest) (*criv1.ListPodSandboxResponse, error) {
+	return c.client.ListPodSandbox(ctx, req)
+}
+
+func (c *cri) ListContainers(ctx context.Context, req *criv1.ListContainersRequest) (*criv1.ListContainersResponse, error) {
+	return c.client.ListContainers(ctx, req)
+}
+
+func (c *cri) ContainerStatus(ctx context.Context, req *criv1.ContainerStatusRequest) (*criv1.ContainerStatusResponse, error) {
+	return c.client.ContainerStatus(ctx, req)
+}
@@ -0,0 +1,164 @@
+package cri
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	criv1 "github.com/aurae-runtime/ae/pkg/api/cri/v1"
+)
+
+// Pod is the summary of a pod sandbox printed by ae.
+type Pod struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	Namespace      string            `json:"namespace"`
+	UID            string            `json:"uid"`
+	Attempt        uint32            `json:"attempt"`
+	State          string            `json:"state"`
+	Created        time.Time         `json:"created"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	RuntimeHandler string            `json:"runtimeHandler,omitempty"`
+}
+
+type Pods []Pod
+
+func NewPods(items []*criv1.PodSandbox) Pods {
+	pods := make(Pods, 0, len(items))
+	for _, item := range items {
+		pods = append(pods, Pod{
+			ID:             item.Id,
+			Name:           item.GetMetadata().GetName(),
+			Namespace:      item.GetMetadata().GetNamespace(),
+			UID:            item.GetMetadata().GetUid(),
+			Attempt:        item.GetMetadata().GetAttempt(),
+			State:          PodStateName(item.State),
+			Created:        time.Unix(0, item.CreatedAt).UTC(),
+			Labels:         item.Labels,
+			RuntimeHandler: item.RuntimeHandler,
+		})
+	}
+	sort.SliceStable(pods, func(i, j int) bool { return pods[i].Created.After(pods[j].Created) })
+	return pods
+}
+
+func (p Pods) String() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "POD ID\tCREATED\tSTATE\tNAME\tNAMESPACE\tATTEMPT\tRUNTIME")
+	for _, pod := range p {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", pod.ID, pod.Created.Format(time.RFC3339), pod.State, pod.Name, pod.Namespace, pod.Attempt, pod.RuntimeHandler)
+	}
+	w.Flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Container is the summary of a container printed by ae.
+type Container struct {
+	ID      string            `json:"id"`
+	PodID   string            `json:"podId"`
+	Name    string            `json:"name"`
+	Image   string            `json:"image"`
+	Attempt uint32            `json:"attempt"`
+	State   string            `json:"state"`
+	Created time.Time         `json:"created"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+type Containers []Container
+
+func NewContainers(items []*criv1.Container) Containers {
+	containers := make(Containers, 0, len(items))
+	for _, item := range items {
+		containers = append(containers, Container{
+			ID:      item.Id,
+			PodID:   item.PodSandboxId,
+			Name:    item.GetMetadata().GetName(),
+			Image:   item.GetImage().GetImage(),
+			Attempt: item.GetMetadata().GetAttempt(),
+			State:   ContainerStateName(item.State),
+			Created: time.Unix(0, item.CreatedAt).UTC(),
+			Labels:  item.Labels,
+		})
+	}
+	sort.SliceStable(containers, func(i, j int) bool { return containers[i].Created.After(containers[j].Created) })
+	return containers
+}
+
+func (c Containers) String() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "CONTAINER\tIMAGE\tCREATED\tSTATE\tNAME\tATTEMPT\tPOD ID")
+	for _, ctr := range c {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", ctr.ID, ctr.Image, ctr.Created.Format(time.RFC3339), ctr.State, ctr.Name, ctr.Attempt, ctr.PodID)
+	}
+	w.Flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+var (
+	podStates = map[criv1.PodSandboxState]string{
+		criv1.PodSandboxState_SANDBOX_READY:    "ready",
+		criv1.PodSandboxState_SANDBOX_NOTREADY: "notready",
+	}
+	containerStates = map[criv1.ContainerState]string{
+		criv1.ContainerState_CONTAINER_CREATED: "created",
+		criv1.ContainerState_CONTAINER_RUNNING: "running",
+		criv1.ContainerState_CONTAINER_EXITED:  "exited",
+		criv1.ContainerState_CONTAINER_UNKNOWN: "unknown",
+	}
+)
+
+func PodStateName(state criv1.PodSandboxState) string {
+	if name, ok := podStates[state]; ok {
+		return name
+	}
+	return fmt.Sprintf("%d", state)
+}
+
+func ContainerStateName(state criv1.ContainerState) string {
+	if name, ok := containerStates[state]; ok {
+		return name
+	}
+	return fmt.Sprintf("%d", state)
+}
+
+// ParsePodState parses a pod state as printed by PodStateName.
+func ParsePodState(name string) (criv1.PodSandboxState, error) {
+	for state, n := range podStates {
+		if strings.EqualFold(name, n) {
+			return state, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown pod state %q, expected ready or notready", name)
+}
+
+// ParseContainerState parses a container state as printed by
+// ContainerStateName.
+func ParseContainerState(name string) (criv1.ContainerState, error) {
+	for state, n := range containerStates {
+		if strings.EqualFold(name, n) {
+			return state, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown container state %q, expected created, running, exited or unknown", name)
+}
+
+// ParseLabels parses a list of key=value pairs as passed to label and
+// annotation flags.
+func ParseLabels(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	labels := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || len(k) == 0 {
+			return nil, fmt.Errorf("invalid label %q, expected key=value", pair)
+		}
+		labels[k] = v
+	}
+	return labels, nil
+}
@@ -0,0 +1,80 @@
+package cri
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	criv1 "github.com/aurae-runtime/ae/pkg/api/cri/v1"
+)
+
+func TestParseLabels(t *testing.T) {
+	ts := []struct {
+		pairs   []string
+		want    map[string]string
+		wanterr bool
+	}{
+		{pairs: nil, want: nil},
+		{pairs: []string{"app=web", "tier="}, want: map[string]string{"app": "web", "tier": ""}},
+		{pairs: []string{"a=b=c"}, want: map[string]string{"a": "b=c"}},
+		{pairs: []string{"app"}, wanterr: true},
+		{pairs: []string{"=web"}, wanterr: true},
+	}
+
+	for _, tt := range ts {
+		got, err := ParseLabels(tt.pairs)
+		if tt.wanterr != (err != nil) {
+			t.Fatalf("%v: want error %v, got %v", tt.pairs, tt.wanterr, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("%v: want %v, got %v", tt.pairs, tt.want, got)
+		}
+	}
+}
+
+func TestParseState(t *testing.T) {
+	if state, err := ParsePodState("NotReady"); err != nil || state != criv1.PodSandboxState_SANDBOX_NOTREADY {
+		t.Fatalf("want notready, got %v (%v)", state, err)
+	}
+	if _, err := ParsePodState("running"); err == nil {
+		t.Fatal("want error for unknown pod state, got no error")
+	}
+	if state, err := ParseContainerState("exited"); err != nil || state != criv1.ContainerState_CONTAINER_EXITED {
+		t.Fatalf("want exited, got %v (%v)", state, err)
+	}
+	if _, err := ParseContainerState("ready"); err == nil {
+		t.Fatal("want error for unknown container state, got no error")
+	}
+}
+
+func TestPodsString(t *testing.T) {
+	pods := NewPods([]*criv1.PodSandbox{
+		{
+			Id:        "older",
+			Metadata:  &criv1.PodSandboxMetadata{Name: "db", Namespace: "default", Uid: "1"},
+			State:     criv1.PodSandboxState_SANDBOX_NOTREADY,
+			CreatedAt: 1e18,
+		},
+		{
+			Id:        "newer",
+			Metadata:  &criv1.PodSandboxMetadata{Name: "web", Namespace: "default", Uid: "2", Attempt: 1},
+			CreatedAt: 2e18,
+		},
+	})
+
+	lines := strings.Split(pods.String(), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("want header and two pods, got %q", lines)
+	}
+	if !strings.HasPrefix(lines[0], "POD ID") {
+		t.Fatalf("want header, got %q", lines[0])
+	}
+	for i, want := range [][]string{
+		{"newer", "2033-05-18T03:33:20Z", "ready", "web", "default", "1"},
+		{"older", "2001-09-09T01:46:40Z", "notready", "db", "default", "0"},
+	} {
+		if got := strings.Fields(lines[i+1]); !reflect.DeepEqual(got, want) {
+			t.Fatalf("want pod %d to be %v, got %q", i, want, got)
+		}
+	}
+}
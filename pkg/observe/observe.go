@@ -11,6 +11,7 @@ import (
 type Observe interface {
 	GetAuraeDaemonLogStream(context.Context, *observev0.GetAuraeDaemonLogStreamRequest) (observev0.ObserveService_GetAuraeDaemonLogStreamClient, error)
 	GetSubProcessStream(context.Context, *observev0.GetSubProcessStreamRequest) (observev0.ObserveService_GetSubProcessStreamClient, error)
+	GetPosixSignalsStream(context.Context, *observev0.GetPosixSignalsStreamRequest) (observev0.ObserveService_GetPosixSignalsStreamClient, error)
 }
 
 type observe struct {
@@ -30,3 +31,7 @@ func (o *observe) GetAuraeDaemonLogStream(ctx context.Context, req *observev0.Ge
 func (o *observe) GetSubProcessStream(ctx context.Context, req *observev0.GetSubProcessStreamRequest) (observev0.ObserveService_GetSubProcessStreamClient, error) {
 	return o.client.GetSubProcessStream(ctx, req)
 }
+
+func (o *observe) GetPosixSignalsStream(ctx context.Context, req *observev0.GetPosixSignalsStreamRequest) (observev0.ObserveService_GetPosixSignalsStreamClient, error) {
+	return o.client.GetPosixSignalsStream(ctx, req)
+}
@@ -0,0 +1,44 @@
+package oci
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var (
+	// ErrInvalidState is matched by errors of operations the runtime-spec
+	// lifecycle does not allow in the current state of a container.
+	ErrInvalidState = errors.New("invalid container state")
+	// ErrUnsupportedSignal is returned for signals that cannot be delivered
+	// through auraed.
+	ErrUnsupportedSignal = errors.New("unsupported signal")
+)
+
+// terminating are the signals delivered by stopping the container process.
+// Signals a process may handle without exiting, like SIGHUP, are not.
+var terminating = map[int32]bool{
+	9:  true, // SIGKILL
+	15: true, // SIGTERM
+}
+
+type InvalidStateError struct {
+	ID     string
+	Op     string
+	Status specs.ContainerState
+	Want   []specs.ContainerState
+}
+
+func (e *InvalidStateError) Error() string {
+	want := make([]string, len(e.Want))
+	for i, s := range e.Want {
+		want[i] = string(s)
+	}
+	return fmt.Sprintf("cannot %s container %s in state %s, must be %s", e.Op, e.ID, e.Status, strings.Join(want, " or "))
+}
+
+func (e *InvalidStateError) Is(target error) bool {
+	return target == ErrInvalidState
+}
@@ -0,0 +1,214 @@
+package oci
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	imagespec "github.com/opencontainers/image-spec/specs-go/v1"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+const defaultPath = "PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// CreateBundle unpacks the image tagged tag from the OCI image layout at
+// layout into a new bundle directory out, consisting of rootfs/ and a
+// config.json generated from the image configuration. out must not exist or
+// be empty, it is removed again if unpacking fails.
+func CreateBundle(layout, tag, out string) ([]string, error) {
+	img, err := ReadImage(layout, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if entries, err := os.ReadDir(out); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("bundle directory %s is not empty", out)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create bundle directory: %w", err)
+	}
+
+	warnings, err := unpackBundle(img, out)
+	if err != nil {
+		_ = os.RemoveAll(out)
+		return warnings, err
+	}
+	return warnings, nil
+}
+
+func unpackBundle(img *Image, out string) ([]string, error) {
+	rootfs := filepath.Join(out, "rootfs")
+	warnings, err := img.Unpack(rootfs)
+	if err != nil {
+		return warnings, err
+	}
+
+	spec, err := DefaultSpec(img, rootfs)
+	if err != nil {
+		return warnings, err
+	}
+	b, err := json.MarshalIndent(spec, "", "\t")
+	if err != nil {
+		return warnings, err
+	}
+	if err := os.WriteFile(filepath.Join(out, ConfigFile), append(b, '\n'), 0o644); err != nil {
+		return warnings, fmt.Errorf("failed to write bundle config: %w", err)
+	}
+	return warnings, nil
+}
+
+// DefaultSpec returns the configuration of a container running the image,
+// with the namespaces and mounts runc uses by default and the process taken
+// from the image configuration. Users given by name are looked up in the
+// /etc/passwd and /etc/group files of rootfs.
+func DefaultSpec(img *Image, rootfs string) (*specs.Spec, error) {
+	cfg := img.Config.Config
+
+	args := append(append([]string{}, cfg.Entrypoint...), cfg.Cmd...)
+	if len(args) == 0 {
+		return nil, errors.New("the image configures neither an entrypoint nor a command")
+	}
+	env := cfg.Env
+	if len(env) == 0 {
+		env = []string{defaultPath}
+	}
+	cwd := cfg.WorkingDir
+	if cwd == "" {
+		cwd = "/"
+	}
+	user, err := resolveUser(rootfs, cfg.User)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations := make(map[string]string)
+	for k, v := range img.Manifest.Annotations {
+		annotations[k] = v
+	}
+	if img.Config.Created != nil {
+		annotations[imagespec.AnnotationCreated] = img.Config.Created.UTC().Format("2006-01-02T15:04:05Z07:00")
+	}
+	for k, v := range cfg.Labels {
+		annotations[k] = v
+	}
+
+	return &specs.Spec{
+		Version: specs.Version,
+		Process: &specs.Process{
+			User: user,
+			Args: args,
+			Env:  env,
+			Cwd:  cwd,
+			Capabilities: &specs.LinuxCapabilities{
+				Bounding:  defaultCapabilities,
+				Effective: defaultCapabilities,
+				Permitted: defaultCapabilities,
+			},
+			NoNewPrivileges: true,
+		},
+		Root: &specs.Root{
+			Path: "rootfs",
+		},
+		Hostname:    "ae",
+		Annotations: annotations,
+		Mounts: []specs.Mount{
+			{Destination: "/proc", Type: "proc", Source: "proc"},
+			{Destination: "/dev", Type: "tmpfs", Source: "tmpfs", Options: []string{"nosuid", "strictatime", "mode=755", "size=65536k"}},
+			{Destination: "/dev/pts", Type: "devpts", Source: "devpts", Options: []string{"nosuid", "noexec", "newinstance", "ptmxmode=0666", "mode=0620", "gid=5"}},
+			{Destination: "/dev/shm", Type: "tmpfs", Source: "shm", Options: []string{"nosuid", "noexec", "nodev", "mode=1777", "size=65536k"}},
+			{Destination: "/dev/mqueue", Type: "mqueue", Source: "mqueue", Options: []string{"nosuid", "noexec", "nodev"}},
+			{Destination: "/sys", Type: "sysfs", Source: "sysfs", Options: []string{"nosuid", "noexec", "nodev", "ro"}},
+		},
+		Linux: &specs.Linux{
+			Namespaces: []specs.LinuxNamespace{
+				{Type: specs.PIDNamespace},
+				{Type: specs.NetworkNamespace},
+				{Type: specs.IPCNamespace},
+				{Type: specs.UTSNamespace},
+				{Type: specs.MountNamespace},
+			},
+		},
+	}, nil
+}
+
+var defaultCapabilities = []string{
+	"CAP_AUDIT_WRITE",
+	"CAP_KILL",
+	"CAP_NET_BIND_SERVICE",
+}
+
+// resolveUser turns the user of an image configuration, one of user, uid,
+// user:group, uid:gid, uid:group or user:gid, into numeric IDs.
+func resolveUser(rootfs, user string) (specs.User, error) {
+	var u specs.User
+	if user == "" {
+		return u, nil
+	}
+	name, group, hasGroup := strings.Cut(user, ":")
+
+	// passwd entries are name:password:uid:gid:...
+	entry, lookupErr := lookupEntry(rootfs, "/etc/passwd", name)
+	if uid, err := strconv.ParseUint(name, 10, 32); err == nil {
+		u.UID = uint32(uid)
+	} else if lookupErr != nil {
+		return u, fmt.Errorf("failed to resolve user %q: %w", name, lookupErr)
+	} else {
+		u.UID = parseID(entry[2])
+	}
+	if lookupErr == nil && len(entry) > 3 {
+		u.GID = parseID(entry[3])
+	}
+
+	if hasGroup {
+		// group entries are name:password:gid:members
+		if gid, err := strconv.ParseUint(group, 10, 32); err == nil {
+			u.GID = uint32(gid)
+		} else if entry, err := lookupEntry(rootfs, "/etc/group", group); err == nil {
+			u.GID = parseID(entry[2])
+		} else {
+			return u, fmt.Errorf("failed to resolve group %q: %w", group, err)
+		}
+	}
+	return u, nil
+}
+
+func parseID(s string) uint32 {
+	n, _ := strconv.ParseUint(s, 10, 32)
+	return uint32(n)
+}
+
+// lookupEntry returns the fields of the entry of a passwd or group file
+// within rootfs whose name, or numeric ID, is key.
+func lookupEntry(rootfs, file, key string) ([]string, error) {
+	p, err := resolveInRoot(rootfs, file)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Split(s.Text(), ":")
+		if len(fields) < 3 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if fields[0] == key || fields[2] == key {
+			return fields, nil
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("not found in %s", file)
+}
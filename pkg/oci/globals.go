@@ -0,0 +1,133 @@
+package oci
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Globals are the global options of runc compatible runtimes, given before
+// the subcommand, e.g. "ae oci --root /run/containerd/runc create ...". The
+// flags are accepted to let container managers use ae as their runtime
+// binary; --systemd-cgroup, --debug, --rootless and --criu are ignored.
+type Globals struct {
+	Root          string
+	Log           string
+	LogFormat     string
+	SystemdCgroup bool
+	Debug         bool
+	Rootless      string
+	Criu          string
+}
+
+func (g *Globals) AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&g.Root, "root", DefaultRoot, "Root directory for the state of containers")
+	cmd.PersistentFlags().StringVar(&g.Log, "log", "", "Write logs to this file instead of stderr")
+	cmd.PersistentFlags().StringVar(&g.LogFormat, "log-format", "text", "Format of the logs, either 'text' or 'json'")
+	cmd.PersistentFlags().BoolVar(&g.SystemdCgroup, "systemd-cgroup", false, "Accepted for runc compatibility and ignored, cgroups are managed by auraed")
+	cmd.PersistentFlags().BoolVar(&g.Debug, "debug", false, "Accepted for runc compatibility and ignored")
+	cmd.PersistentFlags().StringVar(&g.Rootless, "rootless", "auto", "Accepted for runc compatibility and ignored")
+	cmd.PersistentFlags().StringVar(&g.Criu, "criu", "", "Accepted for runc compatibility and ignored")
+	_ = cmd.PersistentFlags().MarkHidden("criu")
+}
+
+// GlobalsFrom returns the global options given to a subcommand of the
+// command the flags have been added to.
+func GlobalsFrom(cmd *cobra.Command) *Globals {
+	g := &Globals{Root: DefaultRoot, LogFormat: "text"}
+	flags := cmd.Flags()
+	if v, err := flags.GetString("root"); err == nil {
+		g.Root = v
+	}
+	if v, err := flags.GetString("log"); err == nil {
+		g.Log = v
+	}
+	if v, err := flags.GetString("log-format"); err == nil {
+		g.LogFormat = v
+	}
+	if v, err := flags.GetBool("systemd-cgroup"); err == nil {
+		g.SystemdCgroup = v
+	}
+	return g
+}
+
+func (g *Globals) Validate() error {
+	if g.Root == "" {
+		return fmt.Errorf("--root must not be empty")
+	}
+	if g.LogFormat != "text" && g.LogFormat != "json" {
+		return fmt.Errorf("unknown --log-format %q, must be 'text' or 'json'", g.LogFormat)
+	}
+	return nil
+}
+
+func (g *Globals) Store() *Store {
+	return NewStore(g.Root)
+}
+
+// SetupLog directs the standard logger to the --log file in the --log-format.
+// JSON logs are written one object per line with level, msg and time, the
+// way container managers read the errors of runc.
+func (g *Globals) SetupLog() error {
+	var w io.Writer = os.Stderr
+	if g.Log != "" {
+		f, err := os.OpenFile(g.Log, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		w = f
+	}
+	if g.LogFormat == "json" {
+		log.SetFlags(0)
+		w = &jsonLog{w: w, now: time.Now}
+	}
+	log.SetOutput(w)
+	return nil
+}
+
+// LogError logs err, if any, so that it ends up in the --log file, and
+// returns it.
+func LogError(err error) error {
+	if err != nil {
+		log.Printf("error: %s", err)
+	}
+	return err
+}
+
+// jsonLog turns the lines written by the standard logger into JSON objects.
+// A leading "error: " or "warning: " sets the level of a line.
+type jsonLog struct {
+	w   io.Writer
+	now func() time.Time
+}
+
+func (j *jsonLog) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		level := "info"
+		for _, l := range []string{"error", "warning"} {
+			if strings.HasPrefix(line, l+": ") {
+				level = l
+				line = strings.TrimPrefix(line, l+": ")
+				break
+			}
+		}
+		b, err := json.Marshal(struct {
+			Level string `json:"level"`
+			Msg   string `json:"msg"`
+			Time  string `json:"time"`
+		}{level, line, j.now().Format(time.RFC3339Nano)})
+		if err != nil {
+			return 0, err
+		}
+		if _, err := j.w.Write(append(b, '\n')); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,67 @@
+package oci
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestGlobalsFrom(t *testing.T) {
+	g := &Globals{}
+	parent := &cobra.Command{Use: "oci"}
+	g.AddFlags(parent)
+
+	var got *Globals
+	child := &cobra.Command{
+		Use: "state",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			got = GlobalsFrom(cmd)
+			return nil
+		},
+	}
+	parent.AddCommand(child)
+	parent.SetArgs([]string{"--root", "/run/containerd/runc/k8s.io", "--log-format", "json", "--systemd-cgroup", "state"})
+	if err := parent.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Root != "/run/containerd/runc/k8s.io" || got.LogFormat != "json" || !got.SystemdCgroup {
+		t.Fatalf("unexpected globals %+v", got)
+	}
+	if err := got.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&Globals{Root: "/run/ae", LogFormat: "logfmt"}).Validate(); err == nil {
+		t.Fatal("want error for unknown log format, got no error")
+	}
+}
+
+func TestJSONLog(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2026, 10, 1, 14, 2, 0, 0, time.UTC)
+	w := &jsonLog{w: &buf, now: func() time.Time { return now }}
+
+	if _, err := w.Write([]byte("error: container web does not exist\nstarted\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	dec := json.NewDecoder(&buf)
+	want := []map[string]string{
+		{"level": "error", "msg": "container web does not exist", "time": "2026-10-01T14:02:00Z"},
+		{"level": "info", "msg": "started", "time": "2026-10-01T14:02:00Z"},
+	}
+	for _, w := range want {
+		got := map[string]string{}
+		if err := dec.Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range w {
+			if got[k] != v {
+				t.Fatalf("want %s %q, got %q", k, v, got[k])
+			}
+		}
+	}
+}
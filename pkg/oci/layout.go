@@ -0,0 +1,179 @@
+package oci
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	digest "github.com/opencontainers/go-digest"
+	imagespec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Image is an image manifest read from an OCI image layout, along with its
+// configuration.
+type Image struct {
+	Layout   string
+	Manifest imagespec.Manifest
+	Config   imagespec.Image
+}
+
+// ParseLayoutRef splits a reference of the form <dir>:<tag>. Without a tag,
+// the only image of the layout is selected.
+func ParseLayoutRef(ref string) (string, string) {
+	i := strings.LastIndex(ref, ":")
+	if i < 0 || strings.ContainsAny(ref[i+1:], `/\`) {
+		return ref, ""
+	}
+	return ref[:i], ref[i+1:]
+}
+
+// ReadImage reads the image tagged tag from the OCI image layout at dir.
+// Image indexes are resolved to the manifest for linux on the architecture
+// ae runs on. Every blob read is verified against its digest.
+func ReadImage(dir, tag string) (*Image, error) {
+	var layout imagespec.ImageLayout
+	if err := readJSON(filepath.Join(dir, imagespec.ImageLayoutFile), &layout); err != nil {
+		return nil, fmt.Errorf("%s is not an OCI image layout: %w", dir, err)
+	}
+	if layout.Version != imagespec.ImageLayoutVersion {
+		return nil, fmt.Errorf("unsupported image layout version %q", layout.Version)
+	}
+
+	var index imagespec.Index
+	if err := readJSON(filepath.Join(dir, imagespec.ImageIndexFile), &index); err != nil {
+		return nil, fmt.Errorf("failed to read image index: %w", err)
+	}
+	desc, err := selectTag(index.Manifests, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	img := &Image{Layout: dir}
+	for depth := 0; desc.MediaType == imagespec.MediaTypeImageIndex; depth++ {
+		if depth > 4 {
+			return nil, errors.New("image indexes are nested too deeply")
+		}
+		var nested imagespec.Index
+		if err := readBlobJSON(dir, desc, &nested); err != nil {
+			return nil, err
+		}
+		if desc, err = selectPlatform(nested.Manifests); err != nil {
+			return nil, err
+		}
+	}
+	if desc.MediaType != imagespec.MediaTypeImageManifest && desc.MediaType != mediaTypeDockerManifest {
+		return nil, fmt.Errorf("unsupported manifest media type %q", desc.MediaType)
+	}
+	if err := readBlobJSON(dir, desc, &img.Manifest); err != nil {
+		return nil, err
+	}
+	if err := readBlobJSON(dir, img.Manifest.Config, &img.Config); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// Media types that are still found in image layouts, but are deprecated or
+// not defined by the image-spec.
+const (
+	mediaTypeNonDistributable     = "application/vnd.oci.image.layer.nondistributable.v1.tar"
+	mediaTypeNonDistributableGzip = "application/vnd.oci.image.layer.nondistributable.v1.tar+gzip"
+	mediaTypeDockerManifest       = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeDockerLayerGzip      = "application/vnd.docker.image.rootfs.diff.tar.gzip"
+	mediaTypeDockerLayerPlain     = "application/vnd.docker.image.rootfs.diff.tar"
+)
+
+func selectTag(manifests []imagespec.Descriptor, tag string) (imagespec.Descriptor, error) {
+	if tag == "" {
+		if len(manifests) != 1 {
+			return imagespec.Descriptor{}, fmt.Errorf("the image layout contains %d images, a tag must be given", len(manifests))
+		}
+		return manifests[0], nil
+	}
+	var tags []string
+	for _, m := range manifests {
+		name := m.Annotations[imagespec.AnnotationRefName]
+		if name == tag {
+			return m, nil
+		}
+		tags = append(tags, name)
+	}
+	return imagespec.Descriptor{}, fmt.Errorf("tag %q not found in image layout, available: %s", tag, strings.Join(tags, ", "))
+}
+
+func selectPlatform(manifests []imagespec.Descriptor) (imagespec.Descriptor, error) {
+	for _, m := range manifests {
+		if m.Platform != nil && m.Platform.OS == "linux" && m.Platform.Architecture == runtime.GOARCH {
+			return m, nil
+		}
+	}
+	return imagespec.Descriptor{}, fmt.Errorf("no image for linux/%s in image index", runtime.GOARCH)
+}
+
+// openBlob opens a blob of the layout. The returned reader fails on EOF if
+// the content does not match the size and digest of the descriptor.
+func openBlob(dir string, desc imagespec.Descriptor) (io.ReadCloser, error) {
+	if err := desc.Digest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid digest %q: %w", desc.Digest, err)
+	}
+	f, err := os.Open(filepath.Join(dir, imagespec.ImageBlobsDir, desc.Digest.Algorithm().String(), desc.Digest.Encoded()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+	return &verifiedBlob{f: f, desc: desc, verifier: desc.Digest.Verifier()}, nil
+}
+
+type verifiedBlob struct {
+	f        *os.File
+	desc     imagespec.Descriptor
+	verifier digest.Verifier
+	n        int64
+}
+
+func (b *verifiedBlob) Read(p []byte) (int, error) {
+	n, err := b.f.Read(p)
+	b.n += int64(n)
+	_, _ = b.verifier.Write(p[:n])
+	if err == io.EOF {
+		if b.n != b.desc.Size {
+			return n, fmt.Errorf("blob %s has size %d, expected %d", b.desc.Digest, b.n, b.desc.Size)
+		}
+		if !b.verifier.Verified() {
+			return n, fmt.Errorf("blob %s does not match its digest", b.desc.Digest)
+		}
+	}
+	return n, err
+}
+
+func (b *verifiedBlob) Close() error {
+	return b.f.Close()
+}
+
+func readBlobJSON(dir string, desc imagespec.Descriptor, v interface{}) error {
+	r, err := openBlob(dir, desc)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("failed to parse blob %s: %w", desc.Digest, err)
+	}
+	return nil
+}
+
+func readJSON(path string, v interface{}) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
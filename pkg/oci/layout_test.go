@@ -0,0 +1,253 @@
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	digest "github.com/opencontainers/go-digest"
+	imagespec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+type tarEntry struct {
+	name     string
+	typeflag byte
+	body     string
+	linkname string
+}
+
+func writeBlob(t *testing.T, dir, mediaType string, b []byte) imagespec.Descriptor {
+	t.Helper()
+	d := digest.FromBytes(b)
+	blobs := filepath.Join(dir, imagespec.ImageBlobsDir, d.Algorithm().String())
+	if err := os.MkdirAll(blobs, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(blobs, d.Encoded()), b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return imagespec.Descriptor{MediaType: mediaType, Digest: d, Size: int64(len(b))}
+}
+
+func writeJSONBlob(t *testing.T, dir, mediaType string, v interface{}) imagespec.Descriptor {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writeBlob(t, dir, mediaType, b)
+}
+
+func writeLayer(t *testing.T, dir string, entries []tarEntry) imagespec.Descriptor {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Typeflag: e.typeflag, Linkname: e.linkname, Mode: 0o644, Size: int64(len(e.body))}
+		if e.typeflag == tar.TypeDir {
+			hdr.Mode = 0o755
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(e.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return writeBlob(t, dir, imagespec.MediaTypeImageLayerGzip, buf.Bytes())
+}
+
+// writeLayout creates an image layout with a single image tagged latest.
+func writeLayout(t *testing.T, outside string) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	base := writeLayer(t, dir, []tarEntry{
+		{name: "etc/", typeflag: tar.TypeDir},
+		{name: "etc/passwd", typeflag: tar.TypeReg, body: "root:x:0:0:root:/root:/bin/sh\nnginx:x:101:102:nginx:/srv:/bin/false\n"},
+		{name: "etc/group", typeflag: tar.TypeReg, body: "root:x:0:\nnginx:x:102:\nwww:x:33:\n"},
+		{name: "usr/lib/", typeflag: tar.TypeDir},
+		{name: "lib", typeflag: tar.TypeSymlink, linkname: "usr/lib"},
+		{name: "esc", typeflag: tar.TypeSymlink, linkname: outside},
+		{name: "a/keep", typeflag: tar.TypeReg, body: "keep"},
+		{name: "a/old", typeflag: tar.TypeReg, body: "old"},
+		{name: "b/x", typeflag: tar.TypeReg, body: "x"},
+	})
+	top := writeLayer(t, dir, []tarEntry{
+		{name: "a/.wh.old", typeflag: tar.TypeReg},
+		{name: "b/y", typeflag: tar.TypeReg, body: "y"},
+		{name: "b/.wh..wh..opq", typeflag: tar.TypeReg},
+		{name: "lib/z", typeflag: tar.TypeReg, body: "z"},
+		{name: "lib/z-link", typeflag: tar.TypeLink, linkname: "usr/lib/z"},
+		{name: "esc/pwned", typeflag: tar.TypeReg, body: "pwned"},
+		{name: "../../evil", typeflag: tar.TypeReg, body: "evil"},
+	})
+
+	config := imagespec.Image{
+		Config: imagespec.ImageConfig{
+			User:       "nginx:www",
+			Entrypoint: []string{"nginx"},
+			Cmd:        []string{"-g", "daemon off;"},
+			WorkingDir: "/srv",
+			Labels:     map[string]string{"org.example.team": "web"},
+		},
+	}
+	manifest := imagespec.Manifest{
+		MediaType: imagespec.MediaTypeImageManifest,
+		Config:    writeJSONBlob(t, dir, imagespec.MediaTypeImageConfig, config),
+		Layers:    []imagespec.Descriptor{base, top},
+	}
+	manifest.SchemaVersion = 2
+	desc := writeJSONBlob(t, dir, imagespec.MediaTypeImageManifest, manifest)
+	desc.Annotations = map[string]string{imagespec.AnnotationRefName: "latest"}
+
+	index := imagespec.Index{MediaType: imagespec.MediaTypeImageIndex, Manifests: []imagespec.Descriptor{desc}}
+	index.SchemaVersion = 2
+	b, _ := json.Marshal(index)
+	if err := os.WriteFile(filepath.Join(dir, imagespec.ImageIndexFile), b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	b, _ = json.Marshal(imagespec.ImageLayout{Version: imagespec.ImageLayoutVersion})
+	if err := os.WriteFile(filepath.Join(dir, imagespec.ImageLayoutFile), b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestParseLayoutRef(t *testing.T) {
+	ts := map[string][2]string{
+		"nginx:latest":      {"nginx", "latest"},
+		"/images/nginx":     {"/images/nginx", ""},
+		"./nginx:1.25":      {"./nginx", "1.25"},
+		"C:\\images\\nginx": {"C:\\images\\nginx", ""},
+	}
+	for ref, want := range ts {
+		dir, tag := ParseLayoutRef(ref)
+		if dir != want[0] || tag != want[1] {
+			t.Fatalf("want %q split into %v, got %q and %q", ref, want, dir, tag)
+		}
+	}
+}
+
+func TestCreateBundle(t *testing.T) {
+	outside := t.TempDir()
+	layout := writeLayout(t, outside)
+	out := filepath.Join(t.TempDir(), "web")
+
+	if _, err := CreateBundle(layout, "latest", out); err != nil {
+		t.Fatalf("want no error, got %s", err)
+	}
+	rootfs := filepath.Join(out, "rootfs")
+
+	for name, want := range map[string]string{
+		"a/keep":           "keep",
+		"b/y":              "y",
+		"usr/lib/z":        "z",
+		"usr/lib/z-link":   "z",
+		"evil":             "evil",
+		outside + "/pwned": "pwned",
+	} {
+		b, err := os.ReadFile(filepath.Join(rootfs, name))
+		if err != nil || string(b) != want {
+			t.Fatalf("want %s to contain %q, got %q (%v)", name, want, b, err)
+		}
+	}
+	for _, name := range []string{"a/old", "b/x"} {
+		if _, err := os.Lstat(filepath.Join(rootfs, name)); !os.IsNotExist(err) {
+			t.Fatalf("want %s to be whited out, got %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outside, "pwned")); !os.IsNotExist(err) {
+		t.Fatalf("want no file written outside of the rootfs, got %v", err)
+	}
+
+	spec, err := LoadSpec(out)
+	if err != nil {
+		t.Fatalf("want generated config to be valid, got %s", err)
+	}
+	if strings.Join(spec.Process.Args, " ") != "nginx -g daemon off;" || spec.Process.Cwd != "/srv" {
+		t.Fatalf("unexpected process %+v", spec.Process)
+	}
+	if spec.Process.User.UID != 101 || spec.Process.User.GID != 33 {
+		t.Fatalf("want user 101:33, got %+v", spec.Process.User)
+	}
+	if spec.Annotations["org.example.team"] != "web" {
+		t.Fatalf("want image labels as annotations, got %v", spec.Annotations)
+	}
+
+	if _, err := CreateBundle(layout, "latest", out); err == nil {
+		t.Fatal("want error for existing bundle, got no error")
+	}
+}
+
+func TestApplyTarHardlinkToSymlink(t *testing.T) {
+	victim := filepath.Join(t.TempDir(), "victim")
+	if err := os.WriteFile(victim, []byte("victim"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Unix(1673085600, 0)
+	if err := os.Chtimes(victim, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, hdr := range []*tar.Header{
+		{Name: "evil", Typeflag: tar.TypeSymlink, Linkname: victim, Mode: 0o777},
+		{Name: "h", Typeflag: tar.TypeLink, Linkname: "evil", Mode: 0o777, ModTime: time.Now()},
+	} {
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rootfs := t.TempDir()
+	if _, err := applyTar(rootfs, tar.NewReader(&buf)); err != nil {
+		t.Fatalf("want no error, got %s", err)
+	}
+	fi, err := os.Stat(victim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0o600 || !fi.ModTime().Equal(modTime) {
+		t.Fatalf("want file outside of the rootfs untouched, got mode %s and mtime %s", fi.Mode(), fi.ModTime())
+	}
+}
+
+func TestReadImageErrors(t *testing.T) {
+	layout := writeLayout(t, t.TempDir())
+	if _, err := ReadImage(layout, "stable"); err == nil || !strings.Contains(err.Error(), "latest") {
+		t.Fatalf("want error listing available tags, got %v", err)
+	}
+
+	img, err := ReadImage(layout, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	layer := img.Manifest.Layers[0]
+	blob := filepath.Join(layout, imagespec.ImageBlobsDir, "sha256", layer.Digest.Encoded())
+	b, _ := os.ReadFile(blob)
+	b[len(b)-1] ^= 0xff
+	if err := os.WriteFile(blob, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := img.Unpack(t.TempDir()); err == nil {
+		t.Fatal("want error for corrupted layer, got no error")
+	}
+}
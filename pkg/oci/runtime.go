@@ -0,0 +1,255 @@
+package oci
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	cellsv0 "github.com/aurae-runtime/ae/pkg/api/v0/cells"
+	"github.com/aurae-runtime/ae/pkg/cells"
+	"github.com/aurae-runtime/ae/pkg/signal"
+)
+
+// Runtime implements the operations of the OCI runtime command line
+// interface on top of Aurae cells. Every container is hosted by a cell of
+// the same name, running the container process as its only executable.
+type Runtime struct {
+	store *Store
+	cells cells.Cells
+}
+
+func NewRuntime(store *Store, c cells.Cells) *Runtime {
+	return &Runtime{
+		store: store,
+		cells: c,
+	}
+}
+
+// CreateOptions are the optional settings of a new container.
+type CreateOptions struct {
+	// PidFile is written with the PID of the container process once it has
+	// been started. The process only exists after start, as the cell alone
+	// is created before.
+	PidFile string
+}
+
+// Create allocates the cell of a new container from the bundle at the given
+// path. It returns the settings of the bundle that could not be translated.
+func (r *Runtime) Create(ctx context.Context, id, bundle string, opts CreateOptions) ([]string, error) {
+	if err := ValidateID(id); err != nil {
+		return nil, err
+	}
+	bundle, err := filepath.Abs(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve bundle path: %w", err)
+	}
+	spec, err := LoadSpec(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	cell, warnings := ToCell(id, spec)
+	exe, uid, gid, exeWarnings := ToExecutable(id, spec)
+	warnings = append(warnings, exeWarnings...)
+
+	c := &Container{
+		State: specs.State{
+			Version:     specs.Version,
+			ID:          id,
+			Status:      specs.StateCreating,
+			Bundle:      bundle,
+			Annotations: spec.Annotations,
+		},
+		Cell:       cell.Name,
+		Executable: exe.Name,
+		Command:    exe.Command,
+		UID:        uid,
+		GID:        gid,
+		Created:    time.Now().UTC(),
+	}
+	if opts.PidFile != "" {
+		if c.PidFile, err = filepath.Abs(opts.PidFile); err != nil {
+			return warnings, fmt.Errorf("failed to resolve pid file path: %w", err)
+		}
+	}
+	if err := r.store.Create(c); err != nil {
+		return warnings, err
+	}
+
+	rsp, err := r.cells.Allocate(ctx, &cellsv0.CellServiceAllocateRequest{Cell: cell})
+	if err != nil {
+		_ = r.store.Delete(id)
+		return warnings, fmt.Errorf("failed to allocate cell: %w", err)
+	}
+	if name := rsp.GetCellName(); name != "" {
+		c.Cell = name
+	}
+	c.State.Status = specs.StateCreated
+	return warnings, r.store.Save(c)
+}
+
+// State returns the runtime-spec state of a container.
+func (r *Runtime) State(ctx context.Context, id string) (*specs.State, error) {
+	c, err := r.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &c.State, nil
+}
+
+// Start runs the process of a created container within its cell.
+func (r *Runtime) Start(ctx context.Context, id string) error {
+	c, err := r.load(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := require(c, "start", specs.StateCreated); err != nil {
+		return err
+	}
+
+	rsp, err := r.cells.Start(ctx, &cellsv0.CellServiceStartRequest{
+		CellName: &c.Cell,
+		Executable: &cellsv0.Executable{
+			Name:        c.Executable,
+			Command:     c.Command,
+			Description: fmt.Sprintf("OCI container %s", id),
+		},
+		Uid: &c.UID,
+		Gid: &c.GID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start container process: %w", err)
+	}
+	c.State.Status = specs.StateRunning
+	c.State.Pid = int(rsp.GetPid())
+	if err := r.store.Save(c); err != nil {
+		return err
+	}
+	if c.PidFile != "" {
+		return writePidFile(c.PidFile, c.State.Pid)
+	}
+	return nil
+}
+
+// Kill stops the process of a created or running container. Auraed stops
+// executables as a whole, so only signals terminating a process are
+// supported.
+func (r *Runtime) Kill(ctx context.Context, id string, sig int32) error {
+	if !terminating[sig] {
+		return fmt.Errorf("%w: %s", ErrUnsupportedSignal, signal.Name(sig))
+	}
+	c, err := r.load(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := require(c, "kill", specs.StateCreated, specs.StateRunning); err != nil {
+		return err
+	}
+	return r.stop(ctx, c)
+}
+
+// Delete frees the cell of a stopped container and forgets the container.
+// With force, created and running containers are stopped first.
+func (r *Runtime) Delete(ctx context.Context, id string, force bool) error {
+	c, err := r.load(ctx, id)
+	if err != nil {
+		return err
+	}
+	if c.State.Status != specs.StateStopped {
+		if !force {
+			return require(c, "delete", specs.StateStopped)
+		}
+		if c.State.Status == specs.StateRunning {
+			if err := r.stop(ctx, c); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = r.cells.Free(ctx, &cellsv0.CellServiceFreeRequest{CellName: c.Cell})
+	if err != nil && status.Code(err) != codes.NotFound {
+		return fmt.Errorf("failed to free cell: %w", err)
+	}
+	return r.store.Delete(id)
+}
+
+func (r *Runtime) stop(ctx context.Context, c *Container) error {
+	if c.State.Status == specs.StateRunning {
+		_, err := r.cells.Stop(ctx, &cellsv0.CellServiceStopRequest{
+			CellName:       &c.Cell,
+			ExecutableName: c.Executable,
+		})
+		if err != nil && status.Code(err) != codes.NotFound {
+			return fmt.Errorf("failed to stop container process: %w", err)
+		}
+	}
+	c.State.Status = specs.StateStopped
+	c.State.Pid = 0
+	return r.store.Save(c)
+}
+
+// load returns a container with its recorded status reconciled with the
+// cells known to auraed: containers whose cell is gone are stopped.
+func (r *Runtime) load(ctx context.Context, id string) (*Container, error) {
+	c, err := r.store.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	if c.State.Status == specs.StateCreating || c.State.Status == specs.StateStopped {
+		return c, nil
+	}
+
+	rsp, err := r.cells.List(ctx, &cellsv0.CellServiceListRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cells: %w", err)
+	}
+	if !hasCell(rsp.GetCells(), c.Cell) {
+		c.State.Status = specs.StateStopped
+		c.State.Pid = 0
+		if err := r.store.Save(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// require fails with an InvalidStateError unless the container is in one of
+// the given states.
+func require(c *Container, op string, states ...specs.ContainerState) error {
+	for _, s := range states {
+		if c.State.Status == s {
+			return nil
+		}
+	}
+	return &InvalidStateError{ID: c.State.ID, Op: op, Status: c.State.Status, Want: states}
+}
+
+// writePidFile replaces the pid file atomically, so readers never observe a
+// partially written PID.
+func writePidFile(path string, pid int) error {
+	tmp := filepath.Join(filepath.Dir(path), "."+filepath.Base(path))
+	if err := os.WriteFile(tmp, []byte(strconv.Itoa(pid)), 0o644); err != nil {
+		return fmt.Errorf("failed to write pid file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write pid file: %w", err)
+	}
+	return nil
+}
+
+func hasCell(nodes []*cellsv0.CellGraphNode, name string) bool {
+	for _, n := range nodes {
+		if n.GetCell().GetName() == name || hasCell(n.GetChildren(), name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,226 @@
+package oci
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+
+	cellsv0 "github.com/aurae-runtime/ae/pkg/api/v0/cells"
+)
+
+type fakeCells struct {
+	allocated   []*cellsv0.Cell
+	allocateErr error
+	listed      []*cellsv0.CellGraphNode
+	started     []*cellsv0.CellServiceStartRequest
+	stopped     []string
+	freed       []string
+}
+
+func (f *fakeCells) Allocate(_ context.Context, req *cellsv0.CellServiceAllocateRequest) (*cellsv0.CellServiceAllocateResponse, error) {
+	if f.allocateErr != nil {
+		return nil, f.allocateErr
+	}
+	f.allocated = append(f.allocated, req.Cell)
+	return &cellsv0.CellServiceAllocateResponse{CellName: req.Cell.Name}, nil
+}
+
+func (f *fakeCells) Free(_ context.Context, req *cellsv0.CellServiceFreeRequest) (*cellsv0.CellServiceFreeResponse, error) {
+	f.freed = append(f.freed, req.CellName)
+	return &cellsv0.CellServiceFreeResponse{}, nil
+}
+
+func (f *fakeCells) Start(_ context.Context, req *cellsv0.CellServiceStartRequest) (*cellsv0.CellServiceStartResponse, error) {
+	f.started = append(f.started, req)
+	return &cellsv0.CellServiceStartResponse{Pid: 4242}, nil
+}
+
+func (f *fakeCells) Stop(_ context.Context, req *cellsv0.CellServiceStopRequest) (*cellsv0.CellServiceStopResponse, error) {
+	f.stopped = append(f.stopped, req.ExecutableName)
+	return &cellsv0.CellServiceStopResponse{}, nil
+}
+
+func (f *fakeCells) List(context.Context, *cellsv0.CellServiceListRequest) (*cellsv0.CellServiceListResponse, error) {
+	return &cellsv0.CellServiceListResponse{Cells: f.listed}, nil
+}
+
+func TestCreate(t *testing.T) {
+	store := NewStore(t.TempDir())
+	cells := &fakeCells{}
+	r := NewRuntime(store, cells)
+	bundle := writeBundle(t, validConfig)
+
+	if _, err := r.Create(context.Background(), "web", bundle, CreateOptions{}); err != nil {
+		t.Fatalf("want no error, got %s", err)
+	}
+	if len(cells.allocated) != 1 || cells.allocated[0].Name != "web" {
+		t.Fatalf("want cell web to be allocated, got %v", cells.allocated)
+	}
+
+	c, err := store.Load("web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.State.Status != specs.StateCreated || c.State.Bundle != bundle || c.State.Annotations["org.example"] != "web" {
+		t.Fatalf("unexpected state %+v", c.State)
+	}
+
+	if _, err := r.Create(context.Background(), "web", bundle, CreateOptions{}); !errors.Is(err, ErrExists) {
+		t.Fatalf("want ErrExists, got %v", err)
+	}
+}
+
+func TestCreateAllocateFails(t *testing.T) {
+	store := NewStore(t.TempDir())
+	r := NewRuntime(store, &fakeCells{allocateErr: errors.New("no cgroup v2")})
+
+	if _, err := r.Create(context.Background(), "web", writeBundle(t, validConfig), CreateOptions{}); err == nil {
+		t.Fatal("want error, got no error")
+	}
+	if _, err := store.Load("web"); !errors.Is(err, ErrNotExist) {
+		t.Fatalf("want state to be removed, got %v", err)
+	}
+}
+
+func TestState(t *testing.T) {
+	store := NewStore(t.TempDir())
+	cells := &fakeCells{}
+	r := NewRuntime(store, cells)
+	if _, err := r.Create(context.Background(), "web", writeBundle(t, validConfig), CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	cells.listed = []*cellsv0.CellGraphNode{{
+		Cell:     &cellsv0.Cell{Name: "parent"},
+		Children: []*cellsv0.CellGraphNode{{Cell: &cellsv0.Cell{Name: "web"}}},
+	}}
+	state, err := r.State(context.Background(), "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Status != specs.StateCreated || state.ID != "web" || state.Version != specs.Version {
+		t.Fatalf("unexpected state %+v", state)
+	}
+
+	cells.listed = nil
+	if state, err = r.State(context.Background(), "web"); err != nil {
+		t.Fatal(err)
+	}
+	if state.Status != specs.StateStopped {
+		t.Fatalf("want container without cell to be stopped, got %s", state.Status)
+	}
+	if c, _ := store.Load("web"); c.State.Status != specs.StateStopped {
+		t.Fatalf("want stopped status to be recorded, got %s", c.State.Status)
+	}
+
+	if _, err := r.State(context.Background(), "db"); !errors.Is(err, ErrNotExist) {
+		t.Fatalf("want ErrNotExist, got %v", err)
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(t.TempDir())
+	cells := &fakeCells{listed: []*cellsv0.CellGraphNode{{Cell: &cellsv0.Cell{Name: "web"}}}}
+	r := NewRuntime(store, cells)
+	if _, err := r.Create(ctx, "web", writeBundle(t, validConfig), CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Delete(ctx, "web", false); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("want created container to be kept without --force, got %v", err)
+	}
+	for _, sig := range []int32{1, 2, 3, 10} {
+		if err := r.Kill(ctx, "web", sig); !errors.Is(err, ErrUnsupportedSignal) {
+			t.Fatalf("want signal %d to be unsupported, got %v", sig, err)
+		}
+	}
+
+	if err := r.Start(ctx, "web"); err != nil {
+		t.Fatal(err)
+	}
+	if len(cells.started) != 1 || *cells.started[0].CellName != "web" || *cells.started[0].Uid != 1000 {
+		t.Fatalf("unexpected start requests %v", cells.started)
+	}
+	state, err := r.State(ctx, "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Status != specs.StateRunning || state.Pid != 4242 {
+		t.Fatalf("unexpected state %+v", state)
+	}
+	if err := r.Start(ctx, "web"); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("want running container not to be started again, got %v", err)
+	}
+	if err := r.Delete(ctx, "web", false); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("want running container to be kept without --force, got %v", err)
+	}
+
+	if err := r.Kill(ctx, "web", 15); err != nil {
+		t.Fatal(err)
+	}
+	if len(cells.stopped) != 1 || cells.stopped[0] != "web" {
+		t.Fatalf("want executable web to be stopped, got %v", cells.stopped)
+	}
+	if err := r.Kill(ctx, "web", 9); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("want stopped container not to be killed, got %v", err)
+	}
+
+	if err := r.Delete(ctx, "web", false); err != nil {
+		t.Fatal(err)
+	}
+	if len(cells.freed) != 1 || cells.freed[0] != "web" {
+		t.Fatalf("want cell web to be freed, got %v", cells.freed)
+	}
+	if _, err := store.Load("web"); !errors.Is(err, ErrNotExist) {
+		t.Fatalf("want state to be removed, got %v", err)
+	}
+}
+
+func TestForceDelete(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(t.TempDir())
+	cells := &fakeCells{listed: []*cellsv0.CellGraphNode{{Cell: &cellsv0.Cell{Name: "web"}}}}
+	r := NewRuntime(store, cells)
+	if _, err := r.Create(ctx, "web", writeBundle(t, validConfig), CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Start(ctx, "web"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Delete(ctx, "web", true); err != nil {
+		t.Fatal(err)
+	}
+	if len(cells.stopped) != 1 || len(cells.freed) != 1 {
+		t.Fatalf("want process stopped and cell freed, got %v and %v", cells.stopped, cells.freed)
+	}
+}
+
+func TestStartWritesPidFile(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	r := NewRuntime(NewStore(dir), &fakeCells{listed: []*cellsv0.CellGraphNode{{Cell: &cellsv0.Cell{Name: "web"}}}})
+	pidFile := filepath.Join(dir, "web.pid")
+
+	if _, err := r.Create(ctx, "web", writeBundle(t, validConfig), CreateOptions{PidFile: pidFile}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(pidFile); !os.IsNotExist(err) {
+		t.Fatalf("want no pid file before start, got %v", err)
+	}
+	if err := r.Start(ctx, "web"); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "4242" {
+		t.Fatalf("want pid 4242, got %q", b)
+	}
+}
@@ -0,0 +1,158 @@
+package oci
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// ConfigFile is the name of the container configuration within a bundle.
+const ConfigFile = "config.json"
+
+var validID = regexp.MustCompile(`^[\w+\-.]+$`)
+
+// ValidateID checks that a container ID is usable as a cell name and as a
+// file name of the state store.
+func ValidateID(id string) error {
+	if id == "" {
+		return errors.New("container id must not be empty")
+	}
+	if !validID.MatchString(id) || id == "." || id == ".." {
+		return fmt.Errorf("invalid container id %q, must only contain letters, digits, '_', '+', '-' and '.'", id)
+	}
+	return nil
+}
+
+// LoadSpec reads and validates the config.json of a bundle. Parse errors are
+// reported with the line and column of the offending value.
+func LoadSpec(bundle string) (*specs.Spec, error) {
+	path := filepath.Join(bundle, ConfigFile)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle config: %w", err)
+	}
+
+	spec := &specs.Spec{}
+	if err := json.Unmarshal(b, spec); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, describeJSONError(b, err))
+	}
+	if err := ValidateSpec(spec); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return spec, nil
+}
+
+func describeJSONError(b []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		line, col := position(b, syntaxErr.Offset)
+		return fmt.Errorf("line %d, column %d: %s", line, col, syntaxErr)
+	case errors.As(err, &typeErr):
+		line, col := position(b, typeErr.Offset)
+		return fmt.Errorf("line %d, column %d: %s must be of type %s, got %s", line, col, typeErr.Field, typeErr.Type, typeErr.Value)
+	default:
+		return err
+	}
+}
+
+func position(b []byte, offset int64) (int, int) {
+	if offset > int64(len(b)) {
+		offset = int64(len(b))
+	}
+	before := b[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := len(before) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
+
+var namespaceTypes = map[specs.LinuxNamespaceType]bool{
+	specs.PIDNamespace:     true,
+	specs.NetworkNamespace: true,
+	specs.MountNamespace:   true,
+	specs.IPCNamespace:     true,
+	specs.UTSNamespace:     true,
+	specs.UserNamespace:    true,
+	specs.CgroupNamespace:  true,
+	specs.TimeNamespace:    true,
+}
+
+// ValidateSpec checks the properties of a container configuration that the
+// runtime-spec marks as required or constrains in value. All violations are
+// reported, each prefixed with the path of the offending property.
+func ValidateSpec(spec *specs.Spec) error {
+	var errs []error
+	fail := func(path, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+	}
+
+	if spec.Version == "" {
+		fail("ociVersion", "is required")
+	} else if !strings.HasPrefix(spec.Version, "1.") {
+		fail("ociVersion", "unsupported version %q, expected 1.x", spec.Version)
+	}
+
+	if spec.Root == nil || spec.Root.Path == "" {
+		fail("root.path", "is required")
+	}
+
+	if spec.Process == nil {
+		fail("process", "is required to create a container")
+	} else {
+		if len(spec.Process.Args) == 0 {
+			fail("process.args", "must contain at least one entry")
+		}
+		if !strings.HasPrefix(spec.Process.Cwd, "/") {
+			fail("process.cwd", "must be an absolute path, got %q", spec.Process.Cwd)
+		}
+		for i, env := range spec.Process.Env {
+			if !strings.Contains(env, "=") {
+				fail(fmt.Sprintf("process.env[%d]", i), "must be of the form KEY=value, got %q", env)
+			}
+		}
+	}
+
+	for i, m := range spec.Mounts {
+		if !strings.HasPrefix(m.Destination, "/") {
+			fail(fmt.Sprintf("mounts[%d].destination", i), "must be an absolute path, got %q", m.Destination)
+		}
+	}
+
+	uts := false
+	if spec.Linux != nil {
+		seen := make(map[specs.LinuxNamespaceType]bool)
+		for i, ns := range spec.Linux.Namespaces {
+			path := fmt.Sprintf("linux.namespaces[%d].type", i)
+			if !namespaceTypes[ns.Type] {
+				fail(path, "unknown namespace type %q", ns.Type)
+			}
+			if seen[ns.Type] {
+				fail(path, "duplicate namespace type %q", ns.Type)
+			}
+			seen[ns.Type] = true
+		}
+		uts = seen[specs.UTSNamespace]
+
+		if r := spec.Linux.Resources; r != nil {
+			if r.Memory != nil && r.Memory.Limit != nil && *r.Memory.Limit < -1 {
+				fail("linux.resources.memory.limit", "must be -1 or positive, got %d", *r.Memory.Limit)
+			}
+			if r.CPU != nil && r.CPU.Quota != nil && *r.CPU.Quota < -1 {
+				fail("linux.resources.cpu.quota", "must be -1 or positive, got %d", *r.CPU.Quota)
+			}
+		}
+	}
+	if spec.Hostname != "" && !uts {
+		fail("hostname", "requires a uts namespace")
+	}
+
+	return errors.Join(errs...)
+}
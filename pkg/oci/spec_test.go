@@ -0,0 +1,100 @@
+package oci
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const validConfig = `{
+	"ociVersion": "1.0.2",
+	"process": {
+		"user": {"uid": 1000, "gid": 1000},
+		"args": ["nginx", "-g", "daemon off;"],
+		"env": ["PATH=/usr/bin"],
+		"cwd": "/"
+	},
+	"root": {"path": "rootfs"},
+	"annotations": {"org.example": "web"},
+	"linux": {
+		"namespaces": [{"type": "pid"}, {"type": "network"}, {"type": "mount"}],
+		"resources": {
+			"cpu": {"shares": 1024, "quota": 50000, "period": 100000, "cpus": "0-1"},
+			"memory": {"limit": 536870912}
+		}
+	}
+}`
+
+func writeBundle(t *testing.T, config string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ConfigFile), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestLoadSpec(t *testing.T) {
+	spec, err := LoadSpec(writeBundle(t, validConfig))
+	if err != nil {
+		t.Fatalf("want no error, got %s", err)
+	}
+	if spec.Process.Args[0] != "nginx" {
+		t.Fatalf("unexpected process %+v", spec.Process)
+	}
+}
+
+func TestLoadSpecErrors(t *testing.T) {
+	ts := []struct {
+		name   string
+		config string
+		want   []string
+	}{
+		{
+			name:   "syntax error",
+			config: "{\n\t\"ociVersion\": \"1.0.2\",\n\t\"process\": {\n}",
+			want:   []string{"line 4"},
+		},
+		{
+			name:   "wrong type",
+			config: "{\n\t\"ociVersion\": \"1.0.2\",\n\t\"process\": {\"args\": \"sh\"}\n}",
+			want:   []string{"line 3", "process.args", "[]string"},
+		},
+		{
+			name:   "missing properties",
+			config: `{"process": {"args": [], "cwd": "tmp"}, "hostname": "web"}`,
+			want:   []string{"ociVersion: is required", "root.path: is required", "process.args", "process.cwd", "hostname: requires a uts namespace"},
+		},
+		{
+			name:   "invalid namespaces",
+			config: `{"ociVersion": "1.0.2", "root": {"path": "rootfs"}, "process": {"args": ["sh"], "cwd": "/"}, "linux": {"namespaces": [{"type": "pid"}, {"type": "pid"}, {"type": "foo"}]}}`,
+			want:   []string{"linux.namespaces[1].type: duplicate", "linux.namespaces[2].type: unknown"},
+		},
+	}
+
+	for _, tt := range ts {
+		_, err := LoadSpec(writeBundle(t, tt.config))
+		if err == nil {
+			t.Fatalf("[%s] want error, got no error", tt.name)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(err.Error(), want) {
+				t.Fatalf("[%s] want error containing %q, got %q", tt.name, want, err)
+			}
+		}
+	}
+}
+
+func TestValidateID(t *testing.T) {
+	for _, id := range []string{"web", "web-1.2_a+b"} {
+		if err := ValidateID(id); err != nil {
+			t.Fatalf("want %q to be valid, got %s", id, err)
+		}
+	}
+	for _, id := range []string{"", "..", "a/b", "a b"} {
+		if err := ValidateID(id); err == nil {
+			t.Fatalf("want %q to be invalid", id)
+		}
+	}
+}
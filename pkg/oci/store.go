@@ -0,0 +1,138 @@
+package oci
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// DefaultRoot is the directory the state of containers is kept in.
+const DefaultRoot = "/run/ae"
+
+var (
+	ErrExists   = errors.New("container already exists")
+	ErrNotExist = errors.New("container does not exist")
+)
+
+// Container is the state ae keeps about a container created from a bundle.
+type Container struct {
+	State      specs.State `json:"state"`
+	Cell       string      `json:"cell"`
+	Executable string      `json:"executable"`
+	Command    string      `json:"command"`
+	UID        uint32      `json:"uid"`
+	GID        uint32      `json:"gid"`
+	PidFile    string      `json:"pidFile,omitempty"`
+	Created    time.Time   `json:"created"`
+}
+
+// Store keeps the state of containers as one JSON file per container below
+// its root directory.
+type Store struct {
+	root string
+}
+
+func NewStore(root string) *Store {
+	return &Store{root: root}
+}
+
+func (s *Store) path(id string) string {
+	return filepath.Join(s.root, id+".json")
+}
+
+// Create records a new container and fails with ErrExists if a container
+// with the same ID is already known.
+func (s *Store) Create(c *Container) error {
+	if err := ValidateID(c.State.ID); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.root, 0o700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	f, err := os.OpenFile(s.path(c.State.ID), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%s: %w", c.State.ID, ErrExists)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create container state: %w", err)
+	}
+	err = json.NewEncoder(f).Encode(c)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write container state: %w", err)
+	}
+	return nil
+}
+
+// Save replaces the recorded state of an existing container.
+func (s *Store) Save(c *Container) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	tmp := s.path(c.State.ID) + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write container state: %w", err)
+	}
+	if err := os.Rename(tmp, s.path(c.State.ID)); err != nil {
+		return fmt.Errorf("failed to write container state: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) Load(id string) (*Container, error) {
+	if err := ValidateID(id); err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%s: %w", id, ErrNotExist)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read container state: %w", err)
+	}
+	c := &Container{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("failed to parse container state of %s: %w", id, err)
+	}
+	return c, nil
+}
+
+func (s *Store) Delete(id string) error {
+	if err := ValidateID(id); err != nil {
+		return err
+	}
+	err := os.Remove(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%s: %w", id, ErrNotExist)
+	}
+	return err
+}
+
+// List returns the IDs of all known containers in lexical order.
+func (s *Store) List() ([]string, error) {
+	entries, err := os.ReadDir(s.root)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	var ids []string
+	for _, e := range entries {
+		if id := strings.TrimSuffix(e.Name(), ".json"); id != e.Name() && !e.IsDir() {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
@@ -0,0 +1,141 @@
+package oci
+
+import (
+	"fmt"
+	"strings"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+
+	cellsv0 "github.com/aurae-runtime/ae/pkg/api/v0/cells"
+)
+
+// ToCell translates the cgroup and namespace settings of a container
+// configuration into the cell hosting the container. Settings without an
+// equivalent in cells are returned as warnings.
+func ToCell(id string, spec *specs.Spec) (*cellsv0.Cell, []string) {
+	cell := &cellsv0.Cell{Name: id}
+	var warnings []string
+
+	if spec.Linux == nil {
+		return cell, warnings
+	}
+
+	for _, ns := range spec.Linux.Namespaces {
+		switch ns.Type {
+		case specs.PIDNamespace:
+			cell.IsolateProcess = true
+		case specs.NetworkNamespace:
+			cell.IsolateNetwork = true
+		case specs.MountNamespace, specs.UTSNamespace, specs.IPCNamespace, specs.CgroupNamespace:
+			// created by auraed along with process isolation
+		default:
+			warnings = append(warnings, fmt.Sprintf("linux.namespaces: %s namespaces are not supported and ignored", ns.Type))
+		}
+		if ns.Path != "" {
+			warnings = append(warnings, fmt.Sprintf("linux.namespaces: joining the %s namespace at %s is not supported", ns.Type, ns.Path))
+		}
+	}
+
+	r := spec.Linux.Resources
+	if r == nil {
+		return cell, warnings
+	}
+	if cpu := r.CPU; cpu != nil {
+		if cpu.Shares != nil || cpu.Quota != nil || cpu.Period != nil {
+			cell.Cpu = &cellsv0.CpuController{Period: cpu.Period}
+			if cpu.Shares != nil {
+				weight := sharesToWeight(*cpu.Shares)
+				cell.Cpu.Weight = &weight
+			}
+			if cpu.Quota != nil && *cpu.Quota > 0 {
+				cell.Cpu.Max = cpu.Quota
+			}
+		}
+		if cpu.Cpus != "" || cpu.Mems != "" {
+			cell.Cpuset = &cellsv0.CpusetController{}
+			if cpu.Cpus != "" {
+				cell.Cpuset.Cpus = &cpu.Cpus
+			}
+			if cpu.Mems != "" {
+				cell.Cpuset.Mems = &cpu.Mems
+			}
+		}
+	}
+	if mem := r.Memory; mem != nil {
+		if mem.Limit != nil || mem.Reservation != nil {
+			cell.Memory = &cellsv0.MemoryController{}
+			if mem.Limit != nil && *mem.Limit > 0 {
+				cell.Memory.Max = mem.Limit
+			}
+			if mem.Reservation != nil && *mem.Reservation > 0 {
+				cell.Memory.Low = mem.Reservation
+			}
+		}
+		if mem.Swap != nil {
+			warnings = append(warnings, "linux.resources.memory.swap is not supported and ignored")
+		}
+	}
+	if r.Pids != nil || r.BlockIO != nil || len(r.HugepageLimits) > 0 || len(r.Devices) > 0 {
+		warnings = append(warnings, "linux.resources: only cpu and memory limits are supported, other limits are ignored")
+	}
+	return cell, warnings
+}
+
+// sharesToWeight converts cgroup v1 cpu shares [2-262144] into a cgroup v2
+// cpu weight [1-10000] the same way runc does.
+func sharesToWeight(shares uint64) uint64 {
+	if shares == 0 {
+		return 0
+	}
+	if shares < 2 {
+		shares = 2
+	}
+	if shares > 262144 {
+		shares = 262144
+	}
+	return 1 + ((shares-2)*9999)/262142
+}
+
+// ToExecutable translates the process of a container configuration into the
+// executable started within its cell, along with the user and group to run
+// it as.
+func ToExecutable(id string, spec *specs.Spec) (*cellsv0.Executable, uint32, uint32, []string) {
+	p := spec.Process
+	var warnings []string
+	if p.Cwd != "/" {
+		warnings = append(warnings, fmt.Sprintf("process.cwd: executables are started in /, not %s", p.Cwd))
+	}
+	if p.Terminal {
+		warnings = append(warnings, "process.terminal is not supported and ignored")
+	}
+	if spec.Root != nil && spec.Root.Path != "" {
+		warnings = append(warnings, "root.path: executables run on the root filesystem of the node")
+	}
+
+	command := shellJoin(p.Args)
+	if len(p.Env) > 0 {
+		command = "env " + shellJoin(p.Env) + " " + command
+	}
+	return &cellsv0.Executable{
+		Name:        id,
+		Command:     command,
+		Description: fmt.Sprintf("OCI container %s", id),
+	}, p.User.UID, p.User.GID, warnings
+}
+
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_-./=:,+@%", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,62 @@
+package oci
+
+import (
+	"testing"
+)
+
+func TestToCell(t *testing.T) {
+	spec, err := LoadSpec(writeBundle(t, validConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cell, warnings := ToCell("web", spec)
+	if len(warnings) != 0 {
+		t.Fatalf("want no warnings, got %v", warnings)
+	}
+	if cell.Name != "web" || !cell.IsolateProcess || !cell.IsolateNetwork {
+		t.Fatalf("unexpected cell %+v", cell)
+	}
+	if *cell.Cpu.Weight != 39 || *cell.Cpu.Max != 50000 || *cell.Cpu.Period != 100000 {
+		t.Fatalf("unexpected cpu controller %+v", cell.Cpu)
+	}
+	if *cell.Cpuset.Cpus != "0-1" || cell.Cpuset.Mems != nil {
+		t.Fatalf("unexpected cpuset controller %+v", cell.Cpuset)
+	}
+	if *cell.Memory.Max != 536870912 {
+		t.Fatalf("unexpected memory controller %+v", cell.Memory)
+	}
+}
+
+func TestToExecutable(t *testing.T) {
+	spec, err := LoadSpec(writeBundle(t, validConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exe, uid, gid, warnings := ToExecutable("web", spec)
+	if want := `env PATH=/usr/bin nginx -g 'daemon off;'`; exe.Command != want {
+		t.Fatalf("want command %q, got %q", want, exe.Command)
+	}
+	if exe.Name != "web" || uid != 1000 || gid != 1000 {
+		t.Fatalf("unexpected executable %+v running as %d:%d", exe, uid, gid)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("want a warning about the root filesystem, got %v", warnings)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	ts := map[string]string{
+		"nginx":       "nginx",
+		"":            "''",
+		"a b":         "'a b'",
+		"it's":        `'it'\''s'`,
+		"--port=8080": "--port=8080",
+	}
+	for in, want := range ts {
+		if got := shellQuote(in); got != want {
+			t.Fatalf("want %q quoted as %q, got %q", in, want, got)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package oci
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	imagespec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = ".wh..wh..opq"
+	maxSymlinks    = 255
+)
+
+// Unpack applies the layers of the image on top of each other into rootfs,
+// handling whiteouts as defined by the image-spec. Device nodes are skipped
+// and reported as warnings, ownership is only applied when running as root.
+func (img *Image) Unpack(rootfs string) ([]string, error) {
+	if err := os.MkdirAll(rootfs, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create rootfs: %w", err)
+	}
+	var warnings []string
+	for i, layer := range img.Manifest.Layers {
+		w, err := img.applyLayer(rootfs, layer)
+		warnings = append(warnings, w...)
+		if err != nil {
+			return warnings, fmt.Errorf("failed to apply layer %d (%s): %w", i, layer.Digest, err)
+		}
+	}
+	return warnings, nil
+}
+
+func (img *Image) applyLayer(rootfs string, desc imagespec.Descriptor) ([]string, error) {
+	blob, err := openBlob(img.Layout, desc)
+	if err != nil {
+		return nil, err
+	}
+	defer blob.Close()
+
+	var r io.Reader = blob
+	switch desc.MediaType {
+	case imagespec.MediaTypeImageLayerGzip, mediaTypeNonDistributableGzip, mediaTypeDockerLayerGzip:
+		gz, err := gzip.NewReader(blob)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	case imagespec.MediaTypeImageLayer, mediaTypeNonDistributable, mediaTypeDockerLayerPlain:
+	default:
+		return nil, fmt.Errorf("unsupported layer media type %q", desc.MediaType)
+	}
+
+	warnings, err := applyTar(rootfs, tar.NewReader(r))
+	if err != nil {
+		return warnings, err
+	}
+	// read any trailing data so that the digest of the blob is verified
+	if _, err := io.Copy(io.Discard, blob); err != nil {
+		return warnings, err
+	}
+	return warnings, nil
+}
+
+func applyTar(rootfs string, tr *tar.Reader) ([]string, error) {
+	var warnings []string
+	// created holds the paths written by this layer, which opaque whiteouts
+	// must keep.
+	created := make(map[string]bool)
+	chown := os.Geteuid() == 0
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return warnings, nil
+		}
+		if err != nil {
+			return warnings, err
+		}
+
+		name := path.Clean("/" + hdr.Name)
+		if name == "/" {
+			continue
+		}
+		dir, base := path.Split(name)
+
+		if base == whiteoutOpaque {
+			if err := removeChildren(rootfs, path.Clean(dir), created); err != nil {
+				return warnings, err
+			}
+			continue
+		}
+		if strings.HasPrefix(base, whiteoutPrefix) {
+			p, err := resolveInRoot(rootfs, path.Join(dir, strings.TrimPrefix(base, whiteoutPrefix)))
+			if err != nil {
+				return warnings, err
+			}
+			if err := os.RemoveAll(p); err != nil {
+				return warnings, err
+			}
+			continue
+		}
+
+		p, err := resolveInRoot(rootfs, name)
+		if err != nil {
+			return warnings, err
+		}
+		for n := name; n != "/"; n = path.Dir(n) {
+			created[n] = true
+		}
+		// layers are not required to carry entries for parent directories
+		if hdr.Typeflag != tar.TypeDir {
+			if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+				return warnings, err
+			}
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if fi, err := os.Lstat(p); err == nil && !fi.IsDir() {
+				if err := os.Remove(p); err != nil {
+					return warnings, err
+				}
+			}
+			if err := os.MkdirAll(p, 0o755); err != nil {
+				return warnings, err
+			}
+		case tar.TypeReg:
+			if err := replace(p); err != nil {
+				return warnings, err
+			}
+			if err := writeFile(p, tr); err != nil {
+				return warnings, err
+			}
+		case tar.TypeSymlink:
+			if err := replace(p); err != nil {
+				return warnings, err
+			}
+			if err := os.Symlink(hdr.Linkname, p); err != nil {
+				return warnings, err
+			}
+		case tar.TypeLink:
+			target, err := resolveInRoot(rootfs, path.Clean("/"+hdr.Linkname))
+			if err != nil {
+				return warnings, err
+			}
+			if err := replace(p); err != nil {
+				return warnings, err
+			}
+			if err := os.Link(target, p); err != nil {
+				return warnings, err
+			}
+		case tar.TypeChar, tar.TypeBlock, tar.TypeFifo:
+			warnings = append(warnings, fmt.Sprintf("skipped device or fifo %s", name))
+			continue
+		default:
+			warnings = append(warnings, fmt.Sprintf("skipped %s of unsupported type %q", name, hdr.Typeflag))
+			continue
+		}
+
+		if chown {
+			if err := os.Lchown(p, hdr.Uid, hdr.Gid); err != nil {
+				return warnings, err
+			}
+		}
+		// hardlinks to symlinks are symlinks themselves, chmod and chtimes
+		// would follow them out of the rootfs
+		fi, err := os.Lstat(p)
+		if err != nil {
+			return warnings, err
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			continue
+		}
+		mode := hdr.FileInfo().Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+		if err := os.Chmod(p, mode); err != nil {
+			return warnings, err
+		}
+		if err := os.Chtimes(p, hdr.AccessTime, hdr.ModTime); err != nil {
+			return warnings, err
+		}
+	}
+}
+
+// resolveInRoot returns the location of name within rootfs. Symlinks in the
+// parent directories of name are followed as if rootfs was the root
+// directory, so entries of a layer can never be written outside of it.
+func resolveInRoot(rootfs, name string) (string, error) {
+	parts := splitPath(name)
+	if len(parts) == 0 {
+		return rootfs, nil
+	}
+
+	cur := "/"
+	links := 0
+	for i := 0; i < len(parts)-1; i++ {
+		next := path.Join(cur, parts[i])
+		fi, err := os.Lstat(filepath.Join(rootfs, filepath.FromSlash(next)))
+		if err != nil || fi.Mode()&os.ModeSymlink == 0 {
+			cur = next
+			continue
+		}
+
+		if links++; links > maxSymlinks {
+			return "", fmt.Errorf("too many levels of symbolic links in %s", name)
+		}
+		target, err := os.Readlink(filepath.Join(rootfs, filepath.FromSlash(next)))
+		if err != nil {
+			return "", err
+		}
+		if !path.IsAbs(target) {
+			target = path.Join(cur, target)
+		}
+		// resolve the link target from the root again, it may contain links
+		// itself
+		parts = append(splitPath(path.Clean("/"+target)), parts[i+1:]...)
+		cur = "/"
+		i = -1
+	}
+	return filepath.Join(rootfs, filepath.FromSlash(path.Join(cur, parts[len(parts)-1]))), nil
+}
+
+func splitPath(name string) []string {
+	var parts []string
+	for _, p := range strings.Split(name, "/") {
+		if p != "" && p != "." {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+// replace removes whatever exists at p, unless it is a directory with
+// content, which cannot be replaced by a file.
+func replace(p string) error {
+	err := os.Remove(p)
+	if err == nil || errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func writeFile(p string, r io.Reader) error {
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// removeChildren implements opaque whiteouts by removing everything below
+// dir that has not been written by the current layer.
+func removeChildren(rootfs, dir string, created map[string]bool) error {
+	p, err := resolveInRoot(rootfs, dir)
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if created[path.Join(dir, e.Name())] {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(p, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package pki
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	StatusCreated  = "created"
+	StatusReused   = "reused"
+	StatusReplaced = "replaced"
+)
+
+// InitEntry describes a certificate of a PKI directory set up by
+// HandleInit.
+type InitEntry struct {
+	File        string    `json:"file" yaml:"file"`
+	Subject     string    `json:"subject" yaml:"subject"`
+	Fingerprint string    `json:"fingerprint" yaml:"fingerprint"`
+	NotAfter    time.Time `json:"notAfter" yaml:"notAfter"`
+	Status      string    `json:"status" yaml:"status"`
+}
+
+type InitSummary struct {
+	Directory    string      `json:"directory" yaml:"directory"`
+	Certificates []InitEntry `json:"certificates" yaml:"certificates"`
+}
+
+func (s *InitSummary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "PKI in %s\n", s.Directory)
+	w := tabwriter.NewWriter(&b, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "FILE\tSUBJECT\tEXPIRES\tSTATUS\tSHA256 FINGERPRINT")
+	for _, e := range s.Certificates {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e.File, e.Subject, e.NotAfter.Format(time.RFC3339), e.Status, e.Fingerprint)
+	}
+	w.Flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// InitOptions configures the certificates created by HandleInit.
+type InitOptions struct {
+	Users []string
+	// DNSNames and IPs are the SANs of the server certificate, where the
+	// DNS names default to ServerName(domain).
+	DNSNames []string
+	IPs      []net.IP
+	// Template configures all certificates that are created. Existing
+	// certificates are reused regardless of the template.
+	Template Template
+}
+
+// HandleInit sets up a complete PKI in dir: the CA, the server certificate
+// of auraed and signed client certificates for every user, using the file
+// names auraed and ae load by default.
+//
+// Existing files are reused, so running it again only adds what is missing.
+// The CA is never overwritten. Server and client certificates that do not
+// verify against the CA, are expired, or do not cover the requested names
+// are issued again.
+func HandleInit(dir, domain string, opts InitOptions) (*InitSummary, error) {
+	for _, user := range opts.Users {
+		if err := validateUser(user); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	summary := &InitSummary{Directory: dir}
+
+	ca, status, err := initCA(dir, domain, opts.Template.CA)
+	if err != nil {
+		return nil, err
+	}
+	if err := summary.add(dir, "ca.crt", ca, status); err != nil {
+		return nil, err
+	}
+
+	dnsNames := opts.DNSNames
+	if len(dnsNames) == 0 {
+		dnsNames = []string{ServerName(domain)}
+	}
+	var names []string
+	names = append(names, dnsNames...)
+	for _, ip := range opts.IPs {
+		names = append(names, ip.String())
+	}
+	srv, status, err := initLeaf(dir, "_signed.server.crt", "server.key", ca, x509.ExtKeyUsageServerAuth, names, func() (*Certificate, error) {
+		return HandleCreateServerCertificate(dir, ca, domain, dnsNames, opts.IPs, opts.Template.Server)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := summary.add(dir, "_signed.server.crt", srv, status); err != nil {
+		return nil, err
+	}
+
+	for _, user := range opts.Users {
+		user := user
+		crtFile := fmt.Sprintf("_signed.client.%s.crt", user)
+		keyFile := fmt.Sprintf("client.%s.key", user)
+		crt, status, err := initLeaf(dir, crtFile, keyFile, ca, x509.ExtKeyUsageClientAuth, nil, func() (*Certificate, error) {
+			csr, err := HandleCreateClientCSR(dir, domain, user, opts.Template.Client)
+			if err != nil {
+				return nil, err
+			}
+			return HandleSignClientCSR(dir, ca, csr, opts.Template.Client)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up client certificate of %q: %w", user, err)
+		}
+		if err := summary.add(dir, crtFile, crt, status); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := restrictPermissions(dir); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// initCA loads the CA in dir or creates it if there is none.
+func initCA(dir, domain string, profile Profile) (*Certificate, string, error) {
+	_, crtErr := os.Stat(filepath.Join(dir, "ca.crt"))
+	_, keyErr := os.Stat(filepath.Join(dir, "ca.key"))
+	switch {
+	case errors.Is(crtErr, fs.ErrNotExist) && errors.Is(keyErr, fs.ErrNotExist):
+		ca, err := HandleCreateAuraeRootCA(dir, domain, profile)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create aurae root ca: %w", err)
+		}
+		return ca, StatusCreated, nil
+	case crtErr != nil || keyErr != nil:
+		return nil, "", fmt.Errorf("incomplete CA in %s, expected both ca.crt and ca.key", dir)
+	}
+
+	ca, err := LoadCA(dir)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load existing CA, refusing to overwrite it: %w", err)
+	}
+	return ca, StatusReused, nil
+}
+
+// initLeaf reuses the certificate and key in dir if they are still valid,
+// and calls create otherwise.
+func initLeaf(dir, crtFile, keyFile string, ca *Certificate, usage x509.ExtKeyUsage, names []string, create func() (*Certificate, error)) (*Certificate, string, error) {
+	crtPem, crtErr := os.ReadFile(filepath.Join(dir, crtFile))
+	keyPem, keyErr := os.ReadFile(filepath.Join(dir, keyFile))
+	if errors.Is(crtErr, fs.ErrNotExist) && errors.Is(keyErr, fs.ErrNotExist) {
+		crt, err := create()
+		return crt, StatusCreated, err
+	}
+
+	if crtErr == nil && keyErr == nil && validLeaf(crtPem, keyPem, ca, usage, names) {
+		return &Certificate{Certificate: string(crtPem), PrivateKey: string(keyPem)}, StatusReused, nil
+	}
+
+	crt, err := create()
+	return crt, StatusReplaced, err
+}
+
+func validLeaf(crtPem, keyPem []byte, ca *Certificate, usage x509.ExtKeyUsage, names []string) bool {
+	leaf, err := verifyIssued(&Certificate{Certificate: string(crtPem), PrivateKey: string(keyPem)}, ca, usage)
+	if err != nil {
+		return false
+	}
+	for _, name := range names {
+		if leaf.VerifyHostname(name) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *InitSummary) add(dir, file string, crt *Certificate, status string) error {
+	c, err := crt.GetCertificate()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	s.Certificates = append(s.Certificates, InitEntry{
+		File:        filepath.Join(dir, file),
+		Subject:     c.Subject.CommonName,
+		Fingerprint: Fingerprint(c),
+		NotAfter:    c.NotAfter,
+		Status:      status,
+	})
+	return nil
+}
+
+// Fingerprint returns the SHA-256 fingerprint of the certificate in the
+// format printed by openssl.
+func Fingerprint(crt *x509.Certificate) string {
+	sum := sha256.Sum256(crt.Raw)
+	return hexColon(sum[:])
+}
+
+// hexColon formats b as colon separated upper case hex bytes.
+func hexColon(b []byte) string {
+	parts := make([]string, len(b))
+	for i, c := range b {
+		parts[i] = fmt.Sprintf("%02X", c)
+	}
+	return strings.Join(parts, ":")
+}
+
+// restrictPermissions makes the private keys in dir readable by the owner
+// only, while certificates and requests stay readable by everyone.
+func restrictPermissions(dir string) error {
+	if err := os.Chmod(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to set permissions of %s: %w", dir, err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		var mode os.FileMode
+		switch filepath.Ext(e.Name()) {
+		case ".key":
+			mode = 0o600
+		case ".crt", ".csr":
+			mode = 0o644
+		default:
+			continue
+		}
+		if err := os.Chmod(filepath.Join(dir, e.Name()), mode); err != nil {
+			return fmt.Errorf("failed to set permissions of %s: %w", e.Name(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package pki
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestInit(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "pki")
+
+	first, err := HandleInit(dir, "unsafe.aurae.io", InitOptions{Users: []string{"nova"}})
+	if err != nil {
+		t.Fatalf("could not initialize pki: %s", err)
+	}
+	if len(first.Certificates) != 3 {
+		t.Fatalf("want CA, server and client certificate, got %+v", first.Certificates)
+	}
+	for _, e := range first.Certificates {
+		if e.Status != StatusCreated {
+			t.Errorf("want %s to be created, got %s", e.File, e.Status)
+		}
+	}
+	for _, file := range []string{"ca.crt", "ca.key", "_signed.server.crt", "server.key", "_signed.client.nova.crt", "client.nova.key"} {
+		fi, err := os.Stat(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("want %s to exist: %s", file, err)
+		}
+		if runtime.GOOS != "windows" && filepath.Ext(file) == ".key" && fi.Mode().Perm() != 0o600 {
+			t.Errorf("want %s to be only readable by the owner, got %s", file, fi.Mode())
+		}
+	}
+
+	// replace the server certificate with one of another CA
+	other := t.TempDir()
+	writeTestCA(t, other, 0)
+	if err := os.Rename(filepath.Join(other, "ca.crt"), filepath.Join(dir, "_signed.server.crt")); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := HandleInit(dir, "unsafe.aurae.io", InitOptions{Users: []string{"nova", "ops"}})
+	if err != nil {
+		t.Fatalf("could not initialize existing pki: %s", err)
+	}
+	want := []string{StatusReused, StatusReplaced, StatusReused, StatusCreated}
+	if len(second.Certificates) != len(want) {
+		t.Fatalf("want %d certificates, got %+v", len(want), second.Certificates)
+	}
+	for i, e := range second.Certificates {
+		if e.Status != want[i] {
+			t.Errorf("want %s to be %s, got %s", e.File, want[i], e.Status)
+		}
+	}
+	if first.Certificates[0].Fingerprint != second.Certificates[0].Fingerprint {
+		t.Errorf("want CA to be reused")
+	}
+
+	if err := os.Remove(filepath.Join(dir, "ca.key")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := HandleInit(dir, "unsafe.aurae.io", InitOptions{}); err == nil {
+		t.Errorf("want error for incomplete CA, got no error")
+	}
+}
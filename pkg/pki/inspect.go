@@ -0,0 +1,320 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package pki
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Inspection describes the certificates, certificate requests and private
+// keys found in a PEM file.
+type Inspection struct {
+	File         string            `json:"file" yaml:"file"`
+	Certificates []CertificateInfo `json:"certificates,omitempty" yaml:"certificates,omitempty"`
+	Requests     []RequestInfo     `json:"requests,omitempty" yaml:"requests,omitempty"`
+	Keys         []KeyInfo         `json:"keys,omitempty" yaml:"keys,omitempty"`
+}
+
+// PublicKeyInfo describes a public key.
+type PublicKeyInfo struct {
+	Algorithm string `json:"algorithm" yaml:"algorithm"`
+	Size      int    `json:"size" yaml:"size"`
+	Curve     string `json:"curve,omitempty" yaml:"curve,omitempty"`
+}
+
+// CertificateInfo describes a certificate.
+type CertificateInfo struct {
+	Subject            string        `json:"subject" yaml:"subject"`
+	Issuer             string        `json:"issuer" yaml:"issuer"`
+	SerialNumber       string        `json:"serialNumber" yaml:"serialNumber"`
+	NotBefore          time.Time     `json:"notBefore" yaml:"notBefore"`
+	NotAfter           time.Time     `json:"notAfter" yaml:"notAfter"`
+	DNSNames           []string      `json:"dnsNames,omitempty" yaml:"dnsNames,omitempty"`
+	IPAddresses        []string      `json:"ipAddresses,omitempty" yaml:"ipAddresses,omitempty"`
+	EmailAddresses     []string      `json:"emailAddresses,omitempty" yaml:"emailAddresses,omitempty"`
+	URIs               []string      `json:"uris,omitempty" yaml:"uris,omitempty"`
+	IsCA               bool          `json:"isCA" yaml:"isCA"`
+	MaxPathLen         *int          `json:"maxPathLen,omitempty" yaml:"maxPathLen,omitempty"`
+	KeyUsage           []string      `json:"keyUsage,omitempty" yaml:"keyUsage,omitempty"`
+	ExtKeyUsage        []string      `json:"extKeyUsage,omitempty" yaml:"extKeyUsage,omitempty"`
+	SubjectKeyID       string        `json:"subjectKeyId,omitempty" yaml:"subjectKeyId,omitempty"`
+	AuthorityKeyID     string        `json:"authorityKeyId,omitempty" yaml:"authorityKeyId,omitempty"`
+	PublicKey          PublicKeyInfo `json:"publicKey" yaml:"publicKey"`
+	SignatureAlgorithm string        `json:"signatureAlgorithm" yaml:"signatureAlgorithm"`
+	Fingerprint        string        `json:"fingerprint" yaml:"fingerprint"`
+}
+
+// RequestInfo describes a certificate request.
+type RequestInfo struct {
+	Subject            string        `json:"subject" yaml:"subject"`
+	DNSNames           []string      `json:"dnsNames,omitempty" yaml:"dnsNames,omitempty"`
+	IPAddresses        []string      `json:"ipAddresses,omitempty" yaml:"ipAddresses,omitempty"`
+	EmailAddresses     []string      `json:"emailAddresses,omitempty" yaml:"emailAddresses,omitempty"`
+	URIs               []string      `json:"uris,omitempty" yaml:"uris,omitempty"`
+	SubjectKeyID       string        `json:"subjectKeyId" yaml:"subjectKeyId"`
+	PublicKey          PublicKeyInfo `json:"publicKey" yaml:"publicKey"`
+	SignatureAlgorithm string        `json:"signatureAlgorithm" yaml:"signatureAlgorithm"`
+	SignatureValid     bool          `json:"signatureValid" yaml:"signatureValid"`
+	Fingerprint        string        `json:"fingerprint" yaml:"fingerprint"`
+}
+
+// KeyInfo describes a private key without revealing it.
+type KeyInfo struct {
+	SubjectKeyID string        `json:"subjectKeyId" yaml:"subjectKeyId"`
+	PublicKey    PublicKeyInfo `json:"publicKey" yaml:"publicKey"`
+	Matches      []KeyMatch    `json:"matches" yaml:"matches"`
+}
+
+// KeyMatch is a certificate or certificate request holding the public key
+// of an inspected private key.
+type KeyMatch struct {
+	File    string `json:"file" yaml:"file"`
+	Subject string `json:"subject" yaml:"subject"`
+}
+
+// InspectFile decodes all PEM blocks of file. Private keys are matched
+// against the certificates and certificate requests in dir, which defaults
+// to the directory of file.
+func InspectFile(file, dir string) (*Inspection, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	if dir == "" {
+		dir = filepath.Dir(file)
+	}
+
+	in := &Inspection{File: file}
+	var candidates []KeyMatch
+	var pubs []crypto.PublicKey
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		switch block.Type {
+		case "CERTIFICATE":
+			crt, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse certificate in %s: %w", file, err)
+			}
+			in.Certificates = append(in.Certificates, inspectCertificate(crt))
+		case "CERTIFICATE REQUEST", "NEW CERTIFICATE REQUEST":
+			csr, err := x509.ParseCertificateRequest(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse certificate request in %s: %w", file, err)
+			}
+			info, err := inspectRequest(csr)
+			if err != nil {
+				return nil, err
+			}
+			in.Requests = append(in.Requests, info)
+		case "PRIVATE KEY", "RSA PRIVATE KEY", "EC PRIVATE KEY":
+			key, err := parsePrivateKeyBlock(block)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse private key in %s: %w", file, err)
+			}
+			ski, err := subjectKeyID(key.Public())
+			if err != nil {
+				return nil, err
+			}
+			if candidates == nil {
+				if candidates, pubs, err = publicKeysIn(dir); err != nil {
+					return nil, err
+				}
+			}
+			info := KeyInfo{
+				SubjectKeyID: hexColon(ski),
+				PublicKey:    inspectPublicKey(key.Public()),
+				Matches:      []KeyMatch{},
+			}
+			for i, pub := range pubs {
+				if key.Public().(publicKey).Equal(pub) {
+					info.Matches = append(info.Matches, candidates[i])
+				}
+			}
+			in.Keys = append(in.Keys, info)
+		default:
+			return nil, fmt.Errorf("unsupported PEM block %q in %s", block.Type, file)
+		}
+	}
+
+	if len(in.Certificates)+len(in.Requests)+len(in.Keys) == 0 {
+		return nil, fmt.Errorf("no PEM encoded certificate, certificate request or private key found in %s", file)
+	}
+	return in, nil
+}
+
+// publicKeysIn returns the public keys of all certificates and certificate
+// requests in the PEM files of dir. Files that cannot be parsed are skipped.
+func publicKeysIn(dir string) ([]KeyMatch, []crypto.PublicKey, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+
+	matches := []KeyMatch{}
+	var pubs []crypto.PublicKey
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		switch filepath.Ext(e.Name()) {
+		case ".crt", ".csr", ".pem":
+		default:
+			continue
+		}
+		file := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for rest := data; ; {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			switch block.Type {
+			case "CERTIFICATE":
+				if crt, err := x509.ParseCertificate(block.Bytes); err == nil {
+					matches = append(matches, KeyMatch{File: file, Subject: crt.Subject.String()})
+					pubs = append(pubs, crt.PublicKey)
+				}
+			case "CERTIFICATE REQUEST", "NEW CERTIFICATE REQUEST":
+				if csr, err := x509.ParseCertificateRequest(block.Bytes); err == nil {
+					matches = append(matches, KeyMatch{File: file, Subject: csr.Subject.String()})
+					pubs = append(pubs, csr.PublicKey)
+				}
+			}
+		}
+	}
+	return matches, pubs, nil
+}
+
+func inspectCertificate(crt *x509.Certificate) CertificateInfo {
+	info := CertificateInfo{
+		Subject:            crt.Subject.String(),
+		Issuer:             crt.Issuer.String(),
+		SerialNumber:       hexColon(crt.SerialNumber.Bytes()),
+		NotBefore:          crt.NotBefore,
+		NotAfter:           crt.NotAfter,
+		DNSNames:           crt.DNSNames,
+		EmailAddresses:     crt.EmailAddresses,
+		IsCA:               crt.IsCA,
+		KeyUsage:           keyUsageNames(crt.KeyUsage),
+		SubjectKeyID:       hexColon(crt.SubjectKeyId),
+		AuthorityKeyID:     hexColon(crt.AuthorityKeyId),
+		PublicKey:          inspectPublicKey(crt.PublicKey),
+		SignatureAlgorithm: crt.SignatureAlgorithm.String(),
+		Fingerprint:        Fingerprint(crt),
+	}
+	for _, ip := range crt.IPAddresses {
+		info.IPAddresses = append(info.IPAddresses, ip.String())
+	}
+	for _, uri := range crt.URIs {
+		info.URIs = append(info.URIs, uri.String())
+	}
+	if crt.IsCA && (crt.MaxPathLen > 0 || crt.MaxPathLenZero) {
+		maxPathLen := crt.MaxPathLen
+		info.MaxPathLen = &maxPathLen
+	}
+	for _, u := range crt.ExtKeyUsage {
+		info.ExtKeyUsage = append(info.ExtKeyUsage, extKeyUsageName(u))
+	}
+	return info
+}
+
+func inspectRequest(csr *x509.CertificateRequest) (RequestInfo, error) {
+	ski, err := subjectKeyID(csr.PublicKey)
+	if err != nil {
+		return RequestInfo{}, err
+	}
+	sum := sha256.Sum256(csr.Raw)
+	info := RequestInfo{
+		Subject:            csr.Subject.String(),
+		DNSNames:           csr.DNSNames,
+		EmailAddresses:     csr.EmailAddresses,
+		SubjectKeyID:       hexColon(ski),
+		PublicKey:          inspectPublicKey(csr.PublicKey),
+		SignatureAlgorithm: csr.SignatureAlgorithm.String(),
+		SignatureValid:     csr.CheckSignature() == nil,
+		Fingerprint:        hexColon(sum[:]),
+	}
+	for _, ip := range csr.IPAddresses {
+		info.IPAddresses = append(info.IPAddresses, ip.String())
+	}
+	for _, uri := range csr.URIs {
+		info.URIs = append(info.URIs, uri.String())
+	}
+	return info, nil
+}
+
+func inspectPublicKey(pub crypto.PublicKey) PublicKeyInfo {
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		return PublicKeyInfo{Algorithm: "RSA", Size: pub.N.BitLen()}
+	case *ecdsa.PublicKey:
+		return PublicKeyInfo{Algorithm: "ECDSA", Size: pub.Curve.Params().BitSize, Curve: pub.Curve.Params().Name}
+	case ed25519.PublicKey:
+		return PublicKeyInfo{Algorithm: "Ed25519", Size: 256}
+	default:
+		return PublicKeyInfo{Algorithm: fmt.Sprintf("%T", pub)}
+	}
+}
+
+// keyUsageNames returns the names of the key usages in the order of
+// RFC 5280, as accepted in templates.
+func keyUsageNames(usage x509.KeyUsage) []string {
+	var names []string
+	for u := x509.KeyUsageDigitalSignature; u <= x509.KeyUsageDecipherOnly; u <<= 1 {
+		if usage&u == 0 {
+			continue
+		}
+		for name, v := range keyUsages {
+			if v == u {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,155 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package pki
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInspectFile(t *testing.T) {
+	dir := t.TempDir()
+	template := Template{
+		CA:     Profile{KeyType: KeyTypeECDSAP256},
+		Server: Profile{KeyType: KeyTypeEd25519},
+		Client: Profile{KeyType: KeyTypeECDSAP384},
+	}
+	if _, err := HandleInit(dir, "unsafe.aurae.io", InitOptions{Users: []string{"nova", "ops"}, Template: template}); err != nil {
+		t.Fatalf("could not initialize pki: %s", err)
+	}
+
+	t.Run("ca", func(t *testing.T) {
+		in, err := InspectFile(filepath.Join(dir, "ca.crt"), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(in.Certificates) != 1 || len(in.Requests) != 0 || len(in.Keys) != 0 {
+			t.Fatalf("want a single certificate, got %+v", in)
+		}
+		crt := in.Certificates[0]
+		if !crt.IsCA || crt.Subject != crt.Issuer || crt.SubjectKeyID == "" {
+			t.Fatalf("want self-signed CA with subject key id, got %+v", crt)
+		}
+		if crt.PublicKey.Algorithm != "ECDSA" || crt.PublicKey.Size != 256 || crt.PublicKey.Curve != "P-256" {
+			t.Fatalf("want ECDSA P-256 key, got %+v", crt.PublicKey)
+		}
+		want := []string{"digitalSignature", "keyCertSign", "cRLSign"}
+		if len(crt.KeyUsage) != len(want) {
+			t.Fatalf("want key usage %v, got %v", want, crt.KeyUsage)
+		}
+		for i := range want {
+			if crt.KeyUsage[i] != want[i] {
+				t.Fatalf("want key usage %v, got %v", want, crt.KeyUsage)
+			}
+		}
+	})
+
+	t.Run("server", func(t *testing.T) {
+		in, err := InspectFile(filepath.Join(dir, "_signed.server.crt"), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ca, err := InspectFile(filepath.Join(dir, "ca.crt"), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		crt := in.Certificates[0]
+		if crt.IsCA || crt.Issuer != ca.Certificates[0].Subject || crt.AuthorityKeyID != ca.Certificates[0].SubjectKeyID {
+			t.Fatalf("want certificate issued by CA, got %+v", crt)
+		}
+		if len(crt.DNSNames) != 1 || crt.DNSNames[0] != "server.unsafe.aurae.io" {
+			t.Fatalf("want DNS name server.unsafe.aurae.io, got %v", crt.DNSNames)
+		}
+		if len(crt.ExtKeyUsage) != 1 || crt.ExtKeyUsage[0] != "serverAuth" {
+			t.Fatalf("want extended key usage serverAuth, got %v", crt.ExtKeyUsage)
+		}
+		if crt.PublicKey.Algorithm != "Ed25519" || len(crt.Fingerprint) != 95 {
+			t.Fatalf("want Ed25519 key and SHA-256 fingerprint, got %+v", crt)
+		}
+	})
+
+	t.Run("request", func(t *testing.T) {
+		in, err := InspectFile(filepath.Join(dir, "client.nova.csr"), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(in.Requests) != 1 || !in.Requests[0].SignatureValid || in.Requests[0].PublicKey.Size != 384 {
+			t.Fatalf("want valid request with P-384 key, got %+v", in)
+		}
+	})
+
+	t.Run("key", func(t *testing.T) {
+		in, err := InspectFile(filepath.Join(dir, "client.nova.key"), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(in.Keys) != 1 {
+			t.Fatalf("want a single key, got %+v", in)
+		}
+		key := in.Keys[0]
+		csr, err := InspectFile(filepath.Join(dir, "client.nova.csr"), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key.SubjectKeyID != csr.Requests[0].SubjectKeyID {
+			t.Fatalf("want subject key id %s, got %s", csr.Requests[0].SubjectKeyID, key.SubjectKeyID)
+		}
+		if len(key.Matches) != 2 ||
+			key.Matches[0].File != filepath.Join(dir, "_signed.client.nova.crt") ||
+			key.Matches[1].File != filepath.Join(dir, "client.nova.csr") {
+			t.Fatalf("want certificate and request of nova, got %+v", key.Matches)
+		}
+
+		// a key inspected outside of its pki finds no matches
+		other := t.TempDir()
+		in, err = InspectFile(filepath.Join(dir, "client.nova.key"), other)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(in.Keys[0].Matches) != 0 {
+			t.Fatalf("want no matches in %s, got %+v", other, in.Keys[0].Matches)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		file := filepath.Join(dir, "garbage.pem")
+		if err := os.WriteFile(file, []byte("not a certificate"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := InspectFile(file, ""); err == nil {
+			t.Fatal("want error for file without PEM blocks, got no error")
+		}
+		if _, err := InspectFile(filepath.Join(dir, "missing.crt"), ""); err == nil {
+			t.Fatal("want error for missing file, got no error")
+		}
+	})
+}
@@ -0,0 +1,147 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package pki
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultIntermediateValidity is the validity of intermediate CAs unless a
+// different one is requested.
+const DefaultIntermediateValidity = 3 * 365 * 24 * time.Hour
+
+// HandleCreateIntermediateCA issues an intermediate CA with the given common
+// name signed by ca, which may be an intermediate itself. The certificate is
+// followed by the chain of ca up to the root. If path is set, the
+// certificate and key are written to ca.crt and ca.key in path, so the
+// directory can be used like the one of the root CA to issue server and
+// client certificates. An existing CA in path is never overwritten.
+func HandleCreateIntermediateCA(path string, ca *Certificate, commonName string, profile Profile) (*Certificate, error) {
+	if path != "" {
+		if _, err := os.Stat(filepath.Join(path, "ca.key")); !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("refusing to overwrite the CA in %s", path)
+		}
+	}
+
+	crtPem, keyPem, err := createIntermediateCA(ca, commonName, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	crt := &Certificate{
+		Certificate: string(crtPem),
+		PrivateKey:  string(keyPem),
+	}
+
+	if path != "" {
+		err = crt.WriteCertificateToFile(path, "ca.crt")
+		if err != nil {
+			return crt, err
+		}
+		err = crt.WritePrivateKeyToFile(path, "ca.key")
+		if err != nil {
+			return crt, err
+		}
+		if crt, err = readCertificate(path, "ca.crt", "ca.key"); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := verifyCA(crt); err != nil {
+		return crt, fmt.Errorf("failed to verify intermediate CA: %w", err)
+	}
+	if _, err := verifyIssued(&Certificate{Certificate: crt.Certificate}, ca, x509.ExtKeyUsageAny); err != nil {
+		return crt, fmt.Errorf("failed to verify intermediate CA: %w", err)
+	}
+
+	return crt, nil
+}
+
+func createIntermediateCA(ca *Certificate, commonName string, profile Profile) ([]byte, []byte, error) {
+	caChain, err := ca.GetChain()
+	if err != nil {
+		return nil, nil, err
+	}
+	// x509 only enforces path lengths when a leaf is verified, so an
+	// intermediate violating them would only fail once it is used
+	for i, crt := range caChain {
+		if crt.MaxPathLen >= 0 && crt.MaxPathLen < i+1 {
+			return nil, nil, fmt.Errorf("CA %q may not issue further intermediate CAs, its max path length is %d", crt.Subject.CommonName, crt.MaxPathLen)
+		}
+	}
+
+	priv, err := generateKey(profile.KeyType.orDefault(DefaultCAKeyType))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		Subject: profile.name(commonName),
+		IsCA:    true,
+	}
+	if err := profile.applyCA(template); err != nil {
+		return nil, nil, err
+	}
+	template.SubjectKeyId, err = subjectKeyID(priv.Public())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	crtPem, err := issueCertificate(ca, template, priv.Public(), profile, DefaultIntermediateValidity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// issueCertificate only adds the intermediates, the root completes the
+	// chain of a CA
+	for _, crt := range caChain {
+		if isSelfSigned(crt) {
+			crtPem = append(crtPem, pem.EncodeToMemory(&pem.Block{
+				Type:  "CERTIFICATE",
+				Bytes: crt.Raw,
+			})...)
+		}
+	}
+
+	keyPem, err := encodePrivateKey(priv)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return crtPem, keyPem, nil
+}
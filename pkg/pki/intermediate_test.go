@@ -0,0 +1,159 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package pki
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+func TestCreateIntermediateCA(t *testing.T) {
+	ecdsa := Profile{KeyType: KeyTypeECDSAP256}
+
+	root, err := HandleCreateAuraeRootCA("", "unsafe.aurae.io", ecdsa)
+	if err != nil {
+		t.Fatalf("could not create root CA: %s", err)
+	}
+	dir := filepath.Join(t.TempDir(), "cluster-1")
+	zero := 0
+	ca, err := HandleCreateIntermediateCA(dir, root, "cluster-1.unsafe.aurae.io", Profile{KeyType: KeyTypeECDSAP256, MaxPathLen: &zero})
+	if err != nil {
+		t.Fatalf("could not create intermediate CA: %s", err)
+	}
+	if _, err := HandleCreateIntermediateCA(dir, root, "cluster-1.unsafe.aurae.io", ecdsa); err == nil {
+		t.Fatal("want error when overwriting a CA, got no error")
+	}
+
+	loaded, err := LoadCA(dir)
+	if err != nil {
+		t.Fatalf("could not load intermediate CA: %s", err)
+	}
+	chain, err := loaded.GetChain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 2 || chain[0].Subject.CommonName != "cluster-1.unsafe.aurae.io" || !chain[0].MaxPathLenZero {
+		t.Fatalf("want intermediate with max path length 0 followed by the root, got %d certificates", len(chain))
+	}
+
+	t.Run("issues leaf certificates with chain", func(t *testing.T) {
+		srv, err := HandleCreateServerCertificate(dir, ca, "unsafe.aurae.io", nil, nil, ecdsa)
+		if err != nil {
+			t.Fatalf("could not create server certificate: %s", err)
+		}
+		req, err := HandleCreateClientCSR(dir, "unsafe.aurae.io", "nova", ecdsa)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client, err := HandleSignClientCSR(dir, ca, req, Profile{})
+		if err != nil {
+			t.Fatalf("could not sign client certificate: %s", err)
+		}
+		for _, c := range []*Certificate{srv, client} {
+			chain, err := c.GetChain()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(chain) != 2 || chain[1].Subject.CommonName != "cluster-1.unsafe.aurae.io" {
+				t.Fatalf("want leaf followed by the intermediate, got %d certificates", len(chain))
+			}
+		}
+
+		report, err := VerifyDirectory(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !report.OK() {
+			t.Fatalf("want valid intermediate pki, got %+v", report.Results)
+		}
+
+		// both sides only trust the root
+		rootCrt, _ := root.GetCertificate()
+		pool := x509.NewCertPool()
+		pool.AddCert(rootCrt)
+		srvPair, err := tls.X509KeyPair([]byte(srv.Certificate), []byte(srv.PrivateKey))
+		if err != nil {
+			t.Fatal(err)
+		}
+		clientPair, err := tls.X509KeyPair([]byte(client.Certificate), []byte(req.PrivateKey))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sc, cc := net.Pipe()
+		defer sc.Close()
+		defer cc.Close()
+		errs := make(chan error, 1)
+		go func() {
+			errs <- tls.Server(sc, &tls.Config{Certificates: []tls.Certificate{srvPair}, ClientCAs: pool, ClientAuth: tls.RequireAndVerifyClientCert}).Handshake()
+		}()
+		if err := tls.Client(cc, &tls.Config{Certificates: []tls.Certificate{clientPair}, RootCAs: pool, ServerName: "server.unsafe.aurae.io"}).Handshake(); err != nil {
+			t.Fatalf("client handshake failed: %s", err)
+		}
+		if err := <-errs; err != nil {
+			t.Fatalf("server handshake failed: %s", err)
+		}
+	})
+
+	t.Run("certificates of the root are not issued by the intermediate", func(t *testing.T) {
+		srv, err := HandleCreateServerCertificate("", root, "unsafe.aurae.io", nil, nil, ecdsa)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := verifyIssued(srv, ca, x509.ExtKeyUsageServerAuth); err == nil {
+			t.Fatal("want error, got no error")
+		}
+	})
+
+	t.Run("path length is enforced", func(t *testing.T) {
+		if _, err := HandleCreateIntermediateCA("", ca, "nested.unsafe.aurae.io", ecdsa); err == nil {
+			t.Fatal("want error for intermediate below max path length 0, got no error")
+		}
+
+		one := 1
+		ca, err := HandleCreateIntermediateCA("", root, "cluster-2.unsafe.aurae.io", Profile{KeyType: KeyTypeECDSAP256, MaxPathLen: &one})
+		if err != nil {
+			t.Fatal(err)
+		}
+		nested, err := HandleCreateIntermediateCA("", ca, "nested.unsafe.aurae.io", ecdsa)
+		if err != nil {
+			t.Fatalf("could not create nested intermediate CA: %s", err)
+		}
+		if chain, _ := nested.GetChain(); len(chain) != 3 {
+			t.Fatalf("want chain of 3 certificates, got %d", len(chain))
+		}
+		if _, err := HandleCreateServerCertificate("", nested, "unsafe.aurae.io", nil, nil, ecdsa); err != nil {
+			t.Fatalf("could not issue from nested intermediate CA: %s", err)
+		}
+	})
+}
@@ -0,0 +1,221 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package pki
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+// KeyType is the algorithm and size of a generated private key.
+type KeyType string
+
+const (
+	KeyTypeRSA2048   KeyType = "rsa2048"
+	KeyTypeRSA4096   KeyType = "rsa4096"
+	KeyTypeECDSAP256 KeyType = "ecdsa-p256"
+	KeyTypeECDSAP384 KeyType = "ecdsa-p384"
+	KeyTypeEd25519   KeyType = "ed25519"
+)
+
+const (
+	// DefaultCAKeyType is used for the CA unless a key type is requested.
+	DefaultCAKeyType = KeyTypeRSA2048
+	// DefaultKeyType is used for server and client keys unless a key type
+	// is requested.
+	DefaultKeyType = KeyTypeRSA4096
+)
+
+// KeyTypes returns all supported key types.
+func KeyTypes() []KeyType {
+	return []KeyType{KeyTypeRSA2048, KeyTypeRSA4096, KeyTypeECDSAP256, KeyTypeECDSAP384, KeyTypeEd25519}
+}
+
+// ParseKeyType parses the name of a key type. An empty name is returned as
+// is, so the default of the certificate being created applies.
+func ParseKeyType(s string) (KeyType, error) {
+	if s == "" {
+		return "", nil
+	}
+	names := make([]string, 0, len(KeyTypes()))
+	for _, kt := range KeyTypes() {
+		if string(kt) == s {
+			return kt, nil
+		}
+		names = append(names, string(kt))
+	}
+	return "", fmt.Errorf("unsupported key type %q, expected one of %s", s, strings.Join(names, ", "))
+}
+
+// keyTypeOf returns the key type of the public key.
+func keyTypeOf(pub crypto.PublicKey) (KeyType, error) {
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		switch pub.N.BitLen() {
+		case 2048:
+			return KeyTypeRSA2048, nil
+		case 4096:
+			return KeyTypeRSA4096, nil
+		}
+		return "", fmt.Errorf("unsupported RSA key size %d", pub.N.BitLen())
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			return KeyTypeECDSAP256, nil
+		case elliptic.P384():
+			return KeyTypeECDSAP384, nil
+		}
+		return "", fmt.Errorf("unsupported ECDSA curve %s", pub.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return KeyTypeEd25519, nil
+	}
+	return "", fmt.Errorf("unsupported public key type %T", pub)
+}
+
+func (kt KeyType) orDefault(def KeyType) KeyType {
+	if kt == "" {
+		return def
+	}
+	return kt
+}
+
+// generateKey creates a private key of the key type.
+func generateKey(kt KeyType) (crypto.Signer, error) {
+	var (
+		key crypto.Signer
+		err error
+	)
+	switch kt {
+	case KeyTypeRSA2048:
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	case KeyTypeRSA4096:
+		key, err = rsa.GenerateKey(rand.Reader, 4096)
+	case KeyTypeECDSAP256:
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case KeyTypeECDSAP384:
+		key, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case KeyTypeEd25519:
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", kt)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate private key: %w", err)
+	}
+	return key, nil
+}
+
+// encodePrivateKey encodes the private key as PEM encoded PKCS#8.
+func encodePrivateKey(key crypto.Signer) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: der,
+	}), nil
+}
+
+// parsePrivateKey parses a PEM encoded PKCS#8 private key. PKCS#1 RSA and
+// SEC 1 EC keys written by older versions and openssl are accepted as well.
+func parsePrivateKey(keyPem string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(keyPem))
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode private key")
+	}
+	return parsePrivateKeyBlock(block)
+}
+
+func parsePrivateKeyBlock(block *pem.Block) (crypto.Signer, error) {
+	var (
+		key any
+		err error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("failed to decode private key: unsupported PEM type %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
+// keyUsage returns the key usage of leaf certificates for the public key.
+// Only RSA keys are used for key encipherment.
+func keyUsage(pub crypto.PublicKey) x509.KeyUsage {
+	usage := x509.KeyUsageDigitalSignature
+	if _, ok := pub.(*rsa.PublicKey); ok {
+		usage |= x509.KeyUsageKeyEncipherment
+	}
+	return usage
+}
+
+// subjectKeyID computes the key identifier of the public key as the SHA-1
+// hash of the subject public key, method (1) of RFC 5280, section 4.2.1.2.
+func subjectKeyID(pub crypto.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+	var spki struct {
+		Algorithm        pkix.AlgorithmIdentifier
+		SubjectPublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(der, &spki); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal public key: %w", err)
+	}
+	id := sha1.Sum(spki.SubjectPublicKey.Bytes)
+	return id[:], nil
+}
@@ -0,0 +1,164 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package pki
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"testing"
+)
+
+func TestParseKeyType(t *testing.T) {
+	for _, kt := range KeyTypes() {
+		if got, err := ParseKeyType(string(kt)); err != nil || got != kt {
+			t.Errorf("want %s, got %s (%v)", kt, got, err)
+		}
+	}
+	if got, err := ParseKeyType(""); err != nil || got != "" {
+		t.Errorf("want empty key type, got %q (%v)", got, err)
+	}
+	if _, err := ParseKeyType("dsa"); err == nil {
+		t.Error("want error for unsupported key type, got no error")
+	}
+}
+
+func TestParsePrivateKeyLegacyEncodings(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDer, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, block := range []*pem.Block{
+		{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)},
+		{Type: "EC PRIVATE KEY", Bytes: ecDer},
+	} {
+		if _, err := parsePrivateKey(string(pem.EncodeToMemory(block))); err != nil {
+			t.Errorf("could not parse %s: %s", block.Type, err)
+		}
+	}
+	if _, err := parsePrivateKey("not a key"); err == nil {
+		t.Error("want error for invalid key, got no error")
+	}
+}
+
+func TestKeyTypes(t *testing.T) {
+	for _, kt := range []KeyType{KeyTypeECDSAP256, KeyTypeECDSAP384, KeyTypeEd25519} {
+		kt := kt
+		t.Run(string(kt), func(t *testing.T) {
+			ca, err := HandleCreateAuraeRootCA("", "unsafe.aurae.io", Profile{KeyType: kt})
+			if err != nil {
+				t.Fatalf("could not create CA: %s", err)
+			}
+			srv, err := HandleCreateServerCertificate("", ca, "unsafe.aurae.io", nil, nil, Profile{KeyType: kt})
+			if err != nil {
+				t.Fatalf("could not create server certificate: %s", err)
+			}
+			req, err := HandleCreateClientCSR("", "unsafe.aurae.io", "nova", Profile{KeyType: kt})
+			if err != nil {
+				t.Fatalf("could not create csr: %s", err)
+			}
+			client, err := HandleSignClientCSR("", ca, req, Profile{})
+			if err != nil {
+				t.Fatalf("could not sign csr: %s", err)
+			}
+
+			block, _ := pem.Decode([]byte(req.PrivateKey))
+			if block == nil || block.Type != "PRIVATE KEY" {
+				t.Fatalf("want PKCS#8 private key, got %q", req.PrivateKey)
+			}
+			key, err := req.GetPrivateKey()
+			if err != nil {
+				t.Fatalf("could not load private key: %s", err)
+			}
+			switch key.(type) {
+			case *ecdsa.PrivateKey:
+				if kt == KeyTypeEd25519 {
+					t.Fatalf("want ed25519 key, got %T", key)
+				}
+			case ed25519.PrivateKey:
+				if kt != KeyTypeEd25519 {
+					t.Fatalf("want ecdsa key, got %T", key)
+				}
+			default:
+				t.Fatalf("unexpected key %T", key)
+			}
+
+			crt, err := client.GetCertificate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if crt.KeyUsage != x509.KeyUsageDigitalSignature {
+				t.Errorf("want only digital signature key usage, got %v", crt.KeyUsage)
+			}
+
+			caCrt, _ := ca.GetCertificate()
+			pool := x509.NewCertPool()
+			pool.AddCert(caCrt)
+			srvPair, err := tls.X509KeyPair([]byte(srv.Certificate), []byte(srv.PrivateKey))
+			if err != nil {
+				t.Fatal(err)
+			}
+			clientPair, err := tls.X509KeyPair([]byte(client.Certificate), []byte(req.PrivateKey))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sc, cc := net.Pipe()
+			defer sc.Close()
+			defer cc.Close()
+			errs := make(chan error, 1)
+			go func() {
+				errs <- tls.Server(sc, &tls.Config{Certificates: []tls.Certificate{srvPair}, ClientCAs: pool, ClientAuth: tls.RequireAndVerifyClientCert}).Handshake()
+			}()
+			if err := tls.Client(cc, &tls.Config{Certificates: []tls.Certificate{clientPair}, RootCAs: pool, ServerName: "server.unsafe.aurae.io"}).Handshake(); err != nil {
+				t.Fatalf("client handshake failed: %s", err)
+			}
+			if err := <-errs; err != nil {
+				t.Fatalf("server handshake failed: %s", err)
+			}
+		})
+	}
+}
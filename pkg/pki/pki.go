@@ -31,9 +31,8 @@
 package pki
 
 import (
+	"crypto"
 	"crypto/rand"
-	"crypto/rsa"
-	"crypto/sha1"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
@@ -44,7 +43,7 @@ import (
 
 type Certificate struct {
 	Certificate string `json:"cert" yaml:"cert"`
-	PrivateKey  string `json:"key" yaml:"key"`
+	PrivateKey  string `json:"key,omitempty" yaml:"key,omitempty"`
 }
 
 func (c *Certificate) GetCertificate() (*x509.Certificate, error) {
@@ -61,22 +60,38 @@ func (c *Certificate) GetCertificate() (*x509.Certificate, error) {
 	return crt, nil
 }
 
+// GetChain returns all certificates of c: the certificate itself followed by
+// the CAs that issued it, as written for certificates of intermediate CAs.
+func (c *Certificate) GetChain() ([]*x509.Certificate, error) {
+	var chain []*x509.Certificate
+	rest := []byte(c.Certificate)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		crt, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		chain = append(chain, crt)
+	}
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("failed to decode certificate")
+	}
+	return chain, nil
+}
+
 func (c *Certificate) GetCertAsString() string {
 	return c.Certificate
 }
 
-func (c *Certificate) GetPrivateKey() (*rsa.PrivateKey, error) {
-	pkPem, _ := pem.Decode([]byte(c.PrivateKey))
-	if pkPem == nil || pkPem.Type != "RSA PRIVATE KEY" {
-		return nil, fmt.Errorf("failed to decode private key")
-	}
-
-	pk, err := x509.ParsePKCS1PrivateKey(pkPem.Bytes)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse private key: %w", err)
-	}
-
-	return pk, nil
+func (c *Certificate) GetPrivateKey() (crypto.Signer, error) {
+	return parsePrivateKey(c.PrivateKey)
 }
 
 func (c *Certificate) GetPrivateKeyAsString() string {
@@ -123,18 +138,8 @@ func (c *CertificateRequest) GetCsrAsString() string {
 	return c.CSR
 }
 
-func (c *CertificateRequest) GetPrivateKey() (*rsa.PrivateKey, error) {
-	pkPem, _ := pem.Decode([]byte(c.PrivateKey))
-	if pkPem == nil || pkPem.Type != "RSA PRIVATE KEY" {
-		return nil, fmt.Errorf("failed to decode private key")
-	}
-
-	pk, err := x509.ParsePKCS1PrivateKey(pkPem.Bytes)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse private key: %w", err)
-	}
-
-	return pk, nil
+func (c *CertificateRequest) GetPrivateKey() (crypto.Signer, error) {
+	return parsePrivateKey(c.PrivateKey)
 }
 
 func (c *CertificateRequest) GetPrivateKeyAsString() string {
@@ -157,8 +162,8 @@ func (c *CertificateRequest) WritePrivateKeyToFile(path, filename string) error
 	return nil
 }
 
-func HandleCreateAuraeRootCA(path string, domainName string) (*Certificate, error) {
-	crtPem, keyPem, err := createCA(domainName)
+func HandleCreateAuraeRootCA(path string, domainName string, profile Profile) (*Certificate, error) {
+	crtPem, keyPem, err := createCA(domainName, profile)
 	if err != nil {
 		return nil, err
 	}
@@ -177,27 +182,32 @@ func HandleCreateAuraeRootCA(path string, domainName string) (*Certificate, erro
 		if err != nil {
 			return ca, err
 		}
+		// verify what ended up on disk rather than what was generated
+		if ca, err = readCertificate(path, "ca.crt", "ca.key"); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := verifyCA(ca); err != nil {
+		return ca, fmt.Errorf("failed to verify CA: %w", err)
 	}
 
 	return ca, nil
 }
 
-func createCA(domainName string) ([]byte, []byte, error) {
-	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+func createCA(domainName string, profile Profile) ([]byte, []byte, error) {
+	priv, err := generateKey(profile.KeyType.orDefault(DefaultCAKeyType))
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to generate private key: %w", err)
+		return nil, nil, err
 	}
 
-	subj := pkix.Name{
-		Organization:       []string{"Aurae"},
-		OrganizationalUnit: []string{"Runtime"},
-		Province:           []string{"aurae"},
-		Locality:           []string{"aurae"},
-		Country:            []string{"IS"},
-		CommonName:         domainName,
-	}
+	subj := profile.name(domainName)
 
 	now := time.Now()
+	notAfter, err := profile.notAfter(now, DefaultCAValidity)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
 	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
@@ -208,12 +218,15 @@ func createCA(domainName string) ([]byte, []byte, error) {
 	template := x509.Certificate{
 		Subject:               subj,
 		NotBefore:             now,
-		NotAfter:              now.Add(24 * time.Hour * 9999),
+		NotAfter:              notAfter,
 		IsCA:                  true,
 		BasicConstraintsValid: true,
 		DNSNames:              []string{domainName},
 		SerialNumber:          serialNumber,
 	}
+	if err := profile.applyCA(&template); err != nil {
+		return nil, nil, err
+	}
 
 	// To get an AuthorityKeyId which is equal to SubjectKeyId, we are manually
 	// setting it according to the rules of x509.go.
@@ -226,13 +239,14 @@ func createCA(domainName string) ([]byte, []byte, error) {
 	// > If SubjectKeyId from template is empty and the template is a CA, SubjectKeyId
 	// > will be generated from the hash of the public key.
 	//
-	// We need a hash of the publickey, so hopefully this link is right
-	// https://stackoverflow.com/questions/52502511/how-to-generate-bytes-array-from-publickey#comment92269419_52502639
-	pubHash := sha1.Sum(priv.N.Bytes())
-	template.SubjectKeyId = pubHash[:]
+	// The hash is computed the same way for all key types, see subjectKeyID.
+	template.SubjectKeyId, err = subjectKeyID(priv.Public())
+	if err != nil {
+		return nil, nil, err
+	}
 	template.AuthorityKeyId = template.SubjectKeyId
 
-	crtBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	crtBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, priv.Public(), priv)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create certificate: %w", err)
 	}
@@ -242,16 +256,29 @@ func createCA(domainName string) ([]byte, []byte, error) {
 		Bytes: crtBytes,
 	})
 
-	keyPem := pem.EncodeToMemory(&pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: crtBytes,
-	})
+	keyPem, err := encodePrivateKey(priv)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	return crtPem, keyPem, nil
 }
 
-func HandleCreateClientCSR(path, domain, user string) (*CertificateRequest, error) {
-	csrPem, keyPem, err := createClientCSR(domain, user)
+// auraeSubject returns the subject used for all certificates of the Aurae
+// PKI with the given common name.
+func auraeSubject(commonName string) pkix.Name {
+	return pkix.Name{
+		Organization:       []string{"Aurae"},
+		OrganizationalUnit: []string{"Runtime"},
+		Province:           []string{"aurae"},
+		Locality:           []string{"aurae"},
+		Country:            []string{"IS"},
+		CommonName:         commonName,
+	}
+}
+
+func HandleCreateClientCSR(path, domain, user string, profile Profile) (*CertificateRequest, error) {
+	csrPem, keyPem, err := createClientCSR(domain, user, profile)
 	if err != nil {
 		return &CertificateRequest{}, err
 	}
@@ -271,30 +298,29 @@ func HandleCreateClientCSR(path, domain, user string) (*CertificateRequest, erro
 		if err != nil {
 			return csr, err
 		}
+		if csr, err = readRequest(path, user); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := verifyRequest(csr); err != nil {
+		return csr, fmt.Errorf("failed to verify certificate request: %w", err)
 	}
 
 	return csr, nil
 }
 
-func createClientCSR(domain, user string) ([]byte, []byte, error) {
-	priv, err := rsa.GenerateKey(rand.Reader, 4096)
+func createClientCSR(domain, user string, profile Profile) ([]byte, []byte, error) {
+	priv, err := generateKey(profile.KeyType.orDefault(DefaultKeyType))
 	if err != nil {
-		return []byte{}, []byte{}, fmt.Errorf("failed to generate private key: %w", err)
+		return []byte{}, []byte{}, err
 	}
 
-	subj := pkix.Name{
-		Organization:       []string{"Aurae"},
-		OrganizationalUnit: []string{"Runtime"},
-		Province:           []string{"aurae"},
-		Locality:           []string{"aurae"},
-		Country:            []string{"IS"},
-		CommonName:         fmt.Sprintf("%s.%s", user, domain),
-	}
+	subj := profile.name(fmt.Sprintf("%s.%s", user, domain))
 
 	template := x509.CertificateRequest{
-		Subject:            subj,
-		SignatureAlgorithm: x509.SHA256WithRSA,
-		DNSNames:           []string{fmt.Sprintf("%s.%s", user, domain)},
+		Subject:  subj,
+		DNSNames: []string{fmt.Sprintf("%s.%s", user, domain)},
 	}
 
 	csrBytes, err := x509.CreateCertificateRequest(rand.Reader, &template, priv)
@@ -307,10 +333,10 @@ func createClientCSR(domain, user string) ([]byte, []byte, error) {
 		Bytes: csrBytes,
 	})
 
-	keyPem := pem.EncodeToMemory(&pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(priv),
-	})
+	keyPem, err := encodePrivateKey(priv)
+	if err != nil {
+		return []byte{}, []byte{}, err
+	}
 
 	return csrPem, keyPem, nil
 }
@@ -45,7 +45,7 @@ func TestCreateAuraeRootCA(t *testing.T) {
 	t.Run("createAuraeCA", func(t *testing.T) {
 		domainName := "unsafe.aurae.io"
 
-		auraeCa, err := HandleCreateAuraeRootCA("", "unsafe.aurae.io")
+		auraeCa, err := HandleCreateAuraeRootCA("", "unsafe.aurae.io", Profile{})
 		if err != nil {
 			t.Errorf("could not create auraeCA")
 		}
@@ -64,7 +64,7 @@ func TestCreateAuraeRootCA(t *testing.T) {
 		path := "_tmp/pki"
 		domainName := "unsafe.aurae.io"
 
-		_, err := HandleCreateAuraeRootCA(path, domainName)
+		_, err := HandleCreateAuraeRootCA(path, domainName, Profile{})
 		if err != nil {
 			t.Errorf("could not create auraeCA")
 		}
@@ -99,7 +99,7 @@ func TestCreateAuraeRootCA(t *testing.T) {
 
 func TestCreateCSR(t *testing.T) {
 	t.Run("createCSR", func(t *testing.T) {
-		clientCsr, err := HandleCreateClientCSR("", "unsafe.aurae.io", "christoph")
+		clientCsr, err := HandleCreateClientCSR("", "unsafe.aurae.io", "christoph", Profile{})
 		if err != nil {
 			t.Errorf("could not create csr")
 		}
@@ -113,7 +113,7 @@ func TestCreateCSR(t *testing.T) {
 
 		keyBytes := []byte(clientCsr.PrivateKey)
 		keyPem, _ := pem.Decode(keyBytes)
-		_, err = x509.ParsePKCS1PrivateKey(keyPem.Bytes)
+		_, err = x509.ParsePKCS8PrivateKey(keyPem.Bytes)
 		if err != nil {
 			t.Errorf("could not parse private key")
 		}
@@ -121,7 +121,7 @@ func TestCreateCSR(t *testing.T) {
 
 	t.Run("createCSR with local files", func(t *testing.T) {
 		path := "_tmp/pki"
-		clientCsr, err := HandleCreateClientCSR(path, "unsafe.aurae.io", "christoph")
+		clientCsr, err := HandleCreateClientCSR(path, "unsafe.aurae.io", "christoph", Profile{})
 		if err != nil {
 			t.Errorf("could not create csr")
 		}
@@ -147,7 +147,7 @@ func TestCreateCSR(t *testing.T) {
 		}
 
 		keyPem, _ := pem.Decode(keyBytes)
-		_, err = x509.ParsePKCS1PrivateKey(keyPem.Bytes)
+		_, err = x509.ParsePKCS8PrivateKey(keyPem.Bytes)
 		if err != nil {
 			t.Errorf("could not parse private key")
 		}
@@ -224,7 +224,7 @@ func TestCreateCSR(t *testing.T) {
 		}
 
 		// Genenerate a new CSR
-		clientCsr, err := HandleCreateClientCSR("", "unsafe.aurae.io", "christoph")
+		clientCsr, err := HandleCreateClientCSR("", "unsafe.aurae.io", "christoph", Profile{})
 		if err != nil {
 			t.Errorf("could create csr")
 		}
@@ -0,0 +1,453 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package pki
+
+import (
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultCAValidity is the validity of the CA unless a different one is
+// requested.
+const DefaultCAValidity = 9999 * 24 * time.Hour
+
+// Duration is a time.Duration read in the format of time.ParseDuration,
+// with the additional units d for days and y for years of 365 days.
+type Duration time.Duration
+
+// ParseDuration parses a duration such as "90d", "5y" or "720h".
+func ParseDuration(s string) (Duration, error) {
+	for unit, d := range map[string]time.Duration{"d": 24 * time.Hour, "y": 365 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, unit); ok {
+			v, err := strconv.ParseUint(n, 10, 32)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return Duration(time.Duration(v) * d), nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return Duration(d), nil
+}
+
+func (d *Duration) UnmarshalYAML(unmarshal func(any) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	v, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+func (d Duration) MarshalYAML() (any, error) {
+	return d.String(), nil
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// String formats whole days as days and anything else like time.Duration.
+func (d Duration) String() string {
+	day := Duration(24 * time.Hour)
+	if d != 0 && d%day == 0 {
+		return fmt.Sprintf("%dd", d/day)
+	}
+	return time.Duration(d).String()
+}
+
+// Subject holds the subject fields of a certificate besides the common name,
+// which is always derived from the domain.
+type Subject struct {
+	Organization       []string `yaml:"organization,omitempty"`
+	OrganizationalUnit []string `yaml:"organizationalUnit,omitempty"`
+	Country            []string `yaml:"country,omitempty"`
+	Province           []string `yaml:"province,omitempty"`
+	Locality           []string `yaml:"locality,omitempty"`
+}
+
+// ParseSubject parses a subject in the format of openssl's -subj flag, e.g.
+// "/O=Acme/OU=Platform/C=DE". Supported fields are O, OU, C, ST and L.
+func ParseSubject(s string) (*Subject, error) {
+	if !strings.HasPrefix(s, "/") {
+		return nil, fmt.Errorf("invalid subject %q, expected /KEY=value/...", s)
+	}
+
+	subject := &Subject{}
+	for _, field := range strings.Split(s[1:], "/") {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid subject field %q, expected KEY=value", field)
+		}
+		switch key {
+		case "O":
+			subject.Organization = append(subject.Organization, value)
+		case "OU":
+			subject.OrganizationalUnit = append(subject.OrganizationalUnit, value)
+		case "C":
+			subject.Country = append(subject.Country, value)
+		case "ST":
+			subject.Province = append(subject.Province, value)
+		case "L":
+			subject.Locality = append(subject.Locality, value)
+		case "CN":
+			return nil, errors.New("the common name is derived from the domain and can not be set")
+		default:
+			return nil, fmt.Errorf("unsupported subject field %q", key)
+		}
+	}
+	return subject, nil
+}
+
+// Profile configures a certificate and its key. The zero value creates the
+// certificates as they have always been created: an RSA key, the Aurae
+// subject, the default validity of the certificate and the key usages
+// required for its purpose.
+type Profile struct {
+	KeyType KeyType `yaml:"keyType,omitempty"`
+	// Subject defaults to O=Aurae, OU=Runtime, C=IS, ST=aurae, L=aurae.
+	Subject *Subject `yaml:"subject,omitempty"`
+	// Validity and NotAfter are mutually exclusive. Leaf certificates are
+	// never valid for longer than the CA.
+	Validity Duration  `yaml:"validity,omitempty"`
+	NotAfter time.Time `yaml:"notAfter,omitempty"`
+	// MaxPathLen and the name constraints are only valid for CAs.
+	MaxPathLen          *int     `yaml:"maxPathLen,omitempty"`
+	PermittedDNSDomains []string `yaml:"permittedDNSDomains,omitempty"`
+	ExcludedDNSDomains  []string `yaml:"excludedDNSDomains,omitempty"`
+	PermittedIPRanges   []string `yaml:"permittedIPRanges,omitempty"`
+	ExcludedIPRanges    []string `yaml:"excludedIPRanges,omitempty"`
+	// KeyUsage and ExtKeyUsage replace the default usages, e.g.
+	// [digitalSignature, keyEncipherment] and [clientAuth].
+	KeyUsage    []string `yaml:"keyUsage,omitempty"`
+	ExtKeyUsage []string `yaml:"extKeyUsage,omitempty"`
+}
+
+var keyUsages = map[string]x509.KeyUsage{
+	"digitalSignature":  x509.KeyUsageDigitalSignature,
+	"contentCommitment": x509.KeyUsageContentCommitment,
+	"keyEncipherment":   x509.KeyUsageKeyEncipherment,
+	"dataEncipherment":  x509.KeyUsageDataEncipherment,
+	"keyAgreement":      x509.KeyUsageKeyAgreement,
+	"keyCertSign":       x509.KeyUsageCertSign,
+	"cRLSign":           x509.KeyUsageCRLSign,
+	"encipherOnly":      x509.KeyUsageEncipherOnly,
+	"decipherOnly":      x509.KeyUsageDecipherOnly,
+}
+
+var extKeyUsages = map[string]x509.ExtKeyUsage{
+	"any":             x509.ExtKeyUsageAny,
+	"serverAuth":      x509.ExtKeyUsageServerAuth,
+	"clientAuth":      x509.ExtKeyUsageClientAuth,
+	"codeSigning":     x509.ExtKeyUsageCodeSigning,
+	"emailProtection": x509.ExtKeyUsageEmailProtection,
+	"timeStamping":    x509.ExtKeyUsageTimeStamping,
+	"OCSPSigning":     x509.ExtKeyUsageOCSPSigning,
+}
+
+// name returns the subject of the profile with the given common name.
+func (p Profile) name(commonName string) pkix.Name {
+	if p.Subject == nil {
+		return auraeSubject(commonName)
+	}
+	return pkix.Name{
+		Organization:       p.Subject.Organization,
+		OrganizationalUnit: p.Subject.OrganizationalUnit,
+		Country:            p.Subject.Country,
+		Province:           p.Subject.Province,
+		Locality:           p.Subject.Locality,
+		CommonName:         commonName,
+	}
+}
+
+// notAfter returns the end of the validity of a certificate issued at now,
+// using def if the profile does not set a validity.
+func (p Profile) notAfter(now time.Time, def time.Duration) (time.Time, error) {
+	switch {
+	case p.Validity != 0 && !p.NotAfter.IsZero():
+		return time.Time{}, errors.New("validity and notAfter are mutually exclusive")
+	case !p.NotAfter.IsZero():
+		if !p.NotAfter.After(now) {
+			return time.Time{}, fmt.Errorf("notAfter %s is in the past", p.NotAfter.Format(time.RFC3339))
+		}
+		return p.NotAfter, nil
+	case p.Validity < 0:
+		return time.Time{}, fmt.Errorf("invalid validity %s", time.Duration(p.Validity))
+	case p.Validity > 0:
+		return now.Add(time.Duration(p.Validity)), nil
+	}
+	return now.Add(def), nil
+}
+
+func (p Profile) keyUsage(def x509.KeyUsage) (x509.KeyUsage, error) {
+	if len(p.KeyUsage) == 0 {
+		return def, nil
+	}
+	var usage x509.KeyUsage
+	for _, name := range p.KeyUsage {
+		u, ok := keyUsages[name]
+		if !ok {
+			return 0, fmt.Errorf("unsupported key usage %q", name)
+		}
+		usage |= u
+	}
+	return usage, nil
+}
+
+func (p Profile) extKeyUsage() ([]x509.ExtKeyUsage, error) {
+	var usages []x509.ExtKeyUsage
+	for _, name := range p.ExtKeyUsage {
+		u, ok := extKeyUsages[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported extended key usage %q", name)
+		}
+		usages = append(usages, u)
+	}
+	return usages, nil
+}
+
+// applyCA sets the key usages and constraints of the profile on the
+// template of a CA certificate.
+func (p Profile) applyCA(template *x509.Certificate) error {
+	var err error
+	template.KeyUsage, err = p.keyUsage(x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature)
+	if err != nil {
+		return err
+	}
+	if template.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return errors.New("the key usage of a CA must include keyCertSign")
+	}
+	if template.ExtKeyUsage, err = p.extKeyUsage(); err != nil {
+		return err
+	}
+
+	if p.MaxPathLen != nil {
+		if *p.MaxPathLen < 0 {
+			return fmt.Errorf("invalid maxPathLen %d", *p.MaxPathLen)
+		}
+		template.MaxPathLen = *p.MaxPathLen
+		template.MaxPathLenZero = *p.MaxPathLen == 0
+	}
+
+	template.PermittedDNSDomains = p.PermittedDNSDomains
+	template.ExcludedDNSDomains = p.ExcludedDNSDomains
+	if template.PermittedIPRanges, err = parseIPRanges(p.PermittedIPRanges); err != nil {
+		return err
+	}
+	if template.ExcludedIPRanges, err = parseIPRanges(p.ExcludedIPRanges); err != nil {
+		return err
+	}
+	template.PermittedDNSDomainsCritical = len(template.PermittedDNSDomains) > 0 || len(template.PermittedIPRanges) > 0
+	return nil
+}
+
+// applyLeaf sets the key usages of the profile on the template of a leaf
+// certificate for pub, which must be valid for usage.
+func (p Profile) applyLeaf(template *x509.Certificate, pub crypto.PublicKey, usage x509.ExtKeyUsage) error {
+	if p.MaxPathLen != nil || len(p.PermittedDNSDomains) > 0 || len(p.ExcludedDNSDomains) > 0 || len(p.PermittedIPRanges) > 0 || len(p.ExcludedIPRanges) > 0 {
+		return errors.New("maxPathLen and name constraints are only valid for CAs")
+	}
+
+	var err error
+	if template.KeyUsage, err = p.keyUsage(keyUsage(pub)); err != nil {
+		return err
+	}
+	if template.ExtKeyUsage, err = p.extKeyUsage(); err != nil {
+		return err
+	}
+	if len(template.ExtKeyUsage) == 0 {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+	}
+	for _, u := range template.ExtKeyUsage {
+		if u == usage || u == x509.ExtKeyUsageAny {
+			return nil
+		}
+	}
+	return fmt.Errorf("the extended key usage must include %s", extKeyUsageName(usage))
+}
+
+func extKeyUsageName(usage x509.ExtKeyUsage) string {
+	for name, u := range extKeyUsages {
+		if u == usage {
+			return name
+		}
+	}
+	return fmt.Sprintf("%d", usage)
+}
+
+func parseIPRanges(ranges []string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, r := range ranges {
+		_, ipNet, err := net.ParseCIDR(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ip range %q: %w", r, err)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// validate checks the profile without creating a certificate.
+func (p Profile) validate(ca bool, usage x509.ExtKeyUsage) error {
+	if _, err := ParseKeyType(string(p.KeyType)); err != nil {
+		return err
+	}
+	if _, err := p.notAfter(time.Now(), time.Hour); err != nil {
+		return err
+	}
+	if ca {
+		return p.applyCA(&x509.Certificate{})
+	}
+	// the public key only decides the default key usage
+	return p.applyLeaf(&x509.Certificate{}, nil, usage)
+}
+
+// Template configures the certificates of a PKI. The subject applies to all
+// profiles that do not set their own.
+type Template struct {
+	Subject      *Subject `yaml:"subject,omitempty"`
+	CA           Profile  `yaml:"ca,omitempty"`
+	Intermediate Profile  `yaml:"intermediate,omitempty"`
+	Server       Profile  `yaml:"server,omitempty"`
+	Client       Profile  `yaml:"client,omitempty"`
+}
+
+// LoadTemplate reads a YAML template, e.g.
+//
+//	subject:
+//	  organization: [Acme]
+//	  country: [DE]
+//	ca:
+//	  keyType: ecdsa-p384
+//	  validity: 5y
+//	  maxPathLen: 0
+//	  permittedDNSDomains: [.acme.internal]
+//	intermediate:
+//	  validity: 1y
+//	server:
+//	  validity: 90d
+//	client:
+//	  validity: 30d
+func LoadTemplate(file string) (*Template, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	t := &Template{}
+	if err := yaml.UnmarshalStrict(b, t); err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", file, err)
+	}
+	for _, p := range []*Profile{&t.CA, &t.Intermediate, &t.Server, &t.Client} {
+		if p.Subject == nil {
+			p.Subject = t.Subject
+		}
+	}
+	if err := t.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", file, err)
+	}
+	return t, nil
+}
+
+// SetSubject sets the subject of all profiles.
+func (t *Template) SetSubject(subject *Subject) {
+	t.Subject = subject
+	for _, p := range []*Profile{&t.CA, &t.Intermediate, &t.Server, &t.Client} {
+		p.Subject = subject
+	}
+}
+
+// SetKeyType sets the key type of all profiles.
+func (t *Template) SetKeyType(keyType KeyType) {
+	for _, p := range []*Profile{&t.CA, &t.Intermediate, &t.Server, &t.Client} {
+		p.KeyType = keyType
+	}
+}
+
+// Validate checks all profiles of the template.
+func (t *Template) Validate() error {
+	if err := t.CA.validate(true, 0); err != nil {
+		return fmt.Errorf("ca: %w", err)
+	}
+	if err := t.Intermediate.validate(true, 0); err != nil {
+		return fmt.Errorf("intermediate: %w", err)
+	}
+	if err := t.Server.validate(false, x509.ExtKeyUsageServerAuth); err != nil {
+		return fmt.Errorf("server: %w", err)
+	}
+	if err := t.Client.validate(false, x509.ExtKeyUsageClientAuth); err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+	return nil
+}
+
+// ParseNotAfter parses the end of a validity given as RFC 3339 timestamp or
+// as date, which is read as midnight UTC.
+func ParseNotAfter(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD or RFC 3339", s)
+	}
+	return t, nil
+}
@@ -0,0 +1,208 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package pki
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSubject(t *testing.T) {
+	got, err := ParseSubject("/O=Acme/OU=Platform/OU=Runtime/C=DE/ST=Berlin/L=Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Subject{
+		Organization:       []string{"Acme"},
+		OrganizationalUnit: []string{"Platform", "Runtime"},
+		Country:            []string{"DE"},
+		Province:           []string{"Berlin"},
+		Locality:           []string{"Berlin"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %+v, got %+v", want, got)
+	}
+
+	for _, s := range []string{"O=Acme", "/O", "/O=", "/CN=x", "/E=a@b.c"} {
+		if _, err := ParseSubject(s); err == nil {
+			t.Errorf("%q: want error, got no error", s)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	ts := map[string]time.Duration{
+		"90d":  90 * 24 * time.Hour,
+		"5y":   5 * 365 * 24 * time.Hour,
+		"720h": 720 * time.Hour,
+	}
+	for s, want := range ts {
+		got, err := ParseDuration(s)
+		if err != nil || time.Duration(got) != want {
+			t.Errorf("%q: want %s, got %s (%v)", s, want, time.Duration(got), err)
+		}
+	}
+	for _, s := range []string{"", "d", "-1d", "1.5y", "forever"} {
+		if _, err := ParseDuration(s); err == nil {
+			t.Errorf("%q: want error, got no error", s)
+		}
+	}
+}
+
+func TestDurationString(t *testing.T) {
+	ts := map[Duration]string{
+		Duration(30 * 24 * time.Hour): "30d",
+		Duration(12 * time.Hour):      "12h0m0s",
+		0:                             "0s",
+	}
+	for d, want := range ts {
+		if got := d.String(); got != want {
+			t.Errorf("%d: want %q, got %q", d, want, got)
+		}
+		b, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Duration
+		if err := json.Unmarshal(b, &got); err != nil || got != d {
+			t.Errorf("%s: want %s after json round trip, got %s (%v)", b, d, got, err)
+		}
+	}
+}
+
+func writeTemplate(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "pki.yaml")
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestLoadTemplate(t *testing.T) {
+	file := writeTemplate(t, `
+subject:
+  organization: [Acme]
+  country: [DE]
+ca:
+  keyType: ecdsa-p256
+  validity: 5y
+  maxPathLen: 0
+  permittedDNSDomains: [unsafe.aurae.io]
+  permittedIPRanges: [10.0.0.0/8]
+server:
+  keyType: ecdsa-p256
+  validity: 30d
+client:
+  keyType: ed25519
+  subject:
+    organization: [Acme Clients]
+  notAfter: 2099-01-01T00:00:00Z
+  keyUsage: [digitalSignature]
+`)
+	template, err := LoadTemplate(file)
+	if err != nil {
+		t.Fatalf("could not load template: %s", err)
+	}
+
+	dir := t.TempDir()
+	if _, err := HandleInit(dir, "unsafe.aurae.io", InitOptions{Users: []string{"nova"}, Template: *template}); err != nil {
+		t.Fatalf("could not initialize pki: %s", err)
+	}
+
+	ca, err := LoadCA(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCrt, _ := ca.GetCertificate()
+	if caCrt.Subject.Organization[0] != "Acme" || caCrt.Subject.Country[0] != "DE" || len(caCrt.Subject.Locality) != 0 {
+		t.Errorf("unexpected CA subject %s", caCrt.Subject)
+	}
+	if d := caCrt.NotAfter.Sub(caCrt.NotBefore); d != 5*365*24*time.Hour {
+		t.Errorf("want CA validity of 5y, got %s", d)
+	}
+	if !caCrt.MaxPathLenZero || caCrt.MaxPathLen != 0 {
+		t.Errorf("want max path length 0, got %d", caCrt.MaxPathLen)
+	}
+	if !reflect.DeepEqual(caCrt.PermittedDNSDomains, []string{"unsafe.aurae.io"}) || len(caCrt.PermittedIPRanges) != 1 {
+		t.Errorf("unexpected name constraints %v %v", caCrt.PermittedDNSDomains, caCrt.PermittedIPRanges)
+	}
+
+	srv, err := readCertificate(dir, "_signed.server.crt", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvCrt, _ := srv.GetCertificate()
+	if d := srvCrt.NotAfter.Sub(srvCrt.NotBefore); d != 30*24*time.Hour {
+		t.Errorf("want server validity of 30d, got %s", d)
+	}
+	if srvCrt.Subject.Organization[0] != "Acme" {
+		t.Errorf("want server subject of the template, got %s", srvCrt.Subject)
+	}
+
+	client, err := readCertificate(dir, "_signed.client.nova.crt", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCrt, _ := client.GetCertificate()
+	if !clientCrt.NotAfter.Equal(time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)) && !clientCrt.NotAfter.Equal(caCrt.NotAfter) {
+		t.Errorf("want client validity until 2099 limited by the CA, got %s", clientCrt.NotAfter)
+	}
+	if clientCrt.Subject.Organization[0] != "Acme Clients" || clientCrt.PublicKeyAlgorithm != x509.Ed25519 {
+		t.Errorf("unexpected client certificate %s with %s key", clientCrt.Subject, clientCrt.PublicKeyAlgorithm)
+	}
+}
+
+func TestLoadTemplateErrors(t *testing.T) {
+	ts := map[string]string{
+		"unknown field":            "ca:\n  lifetime: 5y\n",
+		"unknown key type":         "ca:\n  keyType: dsa\n",
+		"validity and notAfter":    "ca:\n  validity: 5y\n  notAfter: 2099-01-01T00:00:00Z\n",
+		"notAfter in the past":     "server:\n  notAfter: 2000-01-01T00:00:00Z\n",
+		"constraints on leaf":      "server:\n  permittedDNSDomains: [unsafe.aurae.io]\n",
+		"missing server auth":      "server:\n  extKeyUsage: [clientAuth]\n",
+		"unknown key usage":        "client:\n  keyUsage: [everything]\n",
+		"CA without cert sign":     "ca:\n  keyUsage: [digitalSignature]\n",
+		"invalid ip range":         "ca:\n  excludedIPRanges: [10.0.0.1]\n",
+		"invalid validity":         "client:\n  validity: soon\n",
+		"negative max path length": "ca:\n  maxPathLen: -1\n",
+	}
+	for name, content := range ts {
+		if _, err := LoadTemplate(writeTemplate(t, content)); err == nil {
+			t.Errorf("%s: want error, got no error", name)
+		}
+	}
+}
@@ -0,0 +1,409 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package pki
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	ExpiryOK       = "ok"
+	ExpiryExpiring = "expiring"
+	ExpiryExpired  = "expired"
+	StatusRenewed  = "renewed"
+)
+
+// DefaultRenewWithin is the threshold below which certificates are
+// reported as expiring and renewed.
+const DefaultRenewWithin = Duration(30 * 24 * time.Hour)
+
+// ExpiryEntry describes the expiry of a certificate of a PKI directory.
+type ExpiryEntry struct {
+	File     string    `json:"file" yaml:"file"`
+	Subject  string    `json:"subject,omitempty" yaml:"subject,omitempty"`
+	NotAfter time.Time `json:"notAfter,omitempty" yaml:"notAfter,omitempty"`
+	Status   string    `json:"status" yaml:"status"`
+	Backups  []string  `json:"backups,omitempty" yaml:"backups,omitempty"`
+	Error    string    `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type ExpiryReport struct {
+	Directory    string        `json:"directory" yaml:"directory"`
+	Within       Duration      `json:"within" yaml:"within"`
+	Certificates []ExpiryEntry `json:"certificates" yaml:"certificates"`
+}
+
+// OK reports whether no certificate is expiring, expired or failed to
+// renew.
+func (r *ExpiryReport) OK() bool {
+	for _, e := range r.Certificates {
+		if e.Status != ExpiryOK && e.Status != StatusRenewed {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *ExpiryReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "PKI in %s, expiring within %s\n", r.Directory, r.Within)
+	w := tabwriter.NewWriter(&b, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "FILE\tSUBJECT\tEXPIRES\tSTATUS")
+	for _, e := range r.Certificates {
+		expires := ""
+		if !e.NotAfter.IsZero() {
+			expires = e.NotAfter.Format(time.RFC3339)
+		}
+		status := e.Status
+		if e.Error != "" {
+			status = fmt.Sprintf("%s: %s", e.Status, e.Error)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.File, e.Subject, expires, status)
+	}
+	w.Flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// leafFile is a server or client certificate of a PKI directory with the
+// files belonging to it.
+type leafFile struct {
+	crt   string
+	key   string
+	csr   string
+	usage x509.ExtKeyUsage
+}
+
+// leafFiles returns the server certificate and the signed client
+// certificates in dir.
+func leafFiles(dir string) ([]leafFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pki directory: %w", err)
+	}
+
+	var leaves []leafFile
+	if _, err := os.Stat(filepath.Join(dir, "_signed.server.crt")); !errors.Is(err, fs.ErrNotExist) {
+		leaves = append(leaves, leafFile{crt: "_signed.server.crt", key: "server.key", usage: x509.ExtKeyUsageServerAuth})
+	}
+
+	var users []string
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, "_signed.client.") && strings.HasSuffix(name, ".crt") {
+			users = append(users, strings.TrimSuffix(strings.TrimPrefix(name, "_signed.client."), ".crt"))
+		}
+	}
+	sort.Strings(users)
+	for _, user := range users {
+		leaves = append(leaves, leafFile{
+			crt:   fmt.Sprintf("_signed.client.%s.crt", user),
+			key:   fmt.Sprintf("client.%s.key", user),
+			csr:   fmt.Sprintf("client.%s.csr", user),
+			usage: x509.ExtKeyUsageClientAuth,
+		})
+	}
+	return leaves, nil
+}
+
+func expiryStatus(crt *x509.Certificate, now time.Time, within time.Duration) string {
+	switch {
+	case !crt.NotAfter.After(now):
+		return ExpiryExpired
+	case !crt.NotAfter.After(now.Add(within)):
+		return ExpiryExpiring
+	}
+	return ExpiryOK
+}
+
+// CheckExpiry reports the CA, the server certificate and the signed client
+// certificates in dir that expire within the threshold.
+//
+// An error is only returned if dir can not be read; certificates that can
+// not be read are reported in the results.
+func CheckExpiry(dir string, within Duration) (*ExpiryReport, error) {
+	leaves, err := leafFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ExpiryReport{Directory: dir, Within: within}
+	files := []string{"ca.crt"}
+	for _, leaf := range leaves {
+		files = append(files, leaf.crt)
+	}
+	now := time.Now()
+	for _, file := range files {
+		entry := ExpiryEntry{File: file}
+		c, err := readCertificate(dir, file, "")
+		var crt *x509.Certificate
+		if err == nil {
+			crt, err = c.GetCertificate()
+		}
+		if err != nil {
+			if file == "ca.crt" && errors.Is(err, fs.ErrNotExist) {
+				// leaf directories of create-server -d have no CA
+				continue
+			}
+			entry.Status = VerifyError
+			entry.Error = err.Error()
+		} else {
+			entry.Subject = crt.Subject.CommonName
+			entry.NotAfter = crt.NotAfter
+			entry.Status = expiryStatus(crt, now, time.Duration(within))
+		}
+		report.Certificates = append(report.Certificates, entry)
+	}
+	return report, nil
+}
+
+// RenewOptions configures HandleRenew.
+type RenewOptions struct {
+	// CADirectory holds ca.crt and ca.key of the issuing CA and defaults to
+	// the PKI directory.
+	CADirectory string
+	// Rekey generates new private keys of the same type instead of keeping
+	// the existing ones.
+	Rekey bool
+}
+
+// HandleRenew issues the server certificate and the signed client
+// certificates in dir again if they expire within the threshold. The new
+// certificates keep the subject, SANs, key usages and lifetime of the old
+// ones and are signed by the CA that issued them. Private keys are kept
+// unless opts.Rekey is set, in which case client certificate requests are
+// created again as well. Certificates without a key in dir are issued for
+// their existing public key.
+//
+// Replaced files are renamed to <file>.<timestamp>.bak. Certificates that
+// were not issued by the CA, do not match their key or would expire with a
+// CA that expires within the threshold are not renewed and reported as
+// errors. The CA itself is never renewed.
+func HandleRenew(dir string, within Duration, opts RenewOptions) (*ExpiryReport, error) {
+	caDir := opts.CADirectory
+	if caDir == "" {
+		caDir = dir
+	}
+	ca, err := LoadCA(caDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CA: %w", err)
+	}
+	leaves, err := leafFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ExpiryReport{Directory: dir, Within: within}
+	now := time.Now()
+	suffix := fmt.Sprintf(".%s.bak", now.UTC().Format("20060102T150405Z"))
+	for _, leaf := range leaves {
+		entry := ExpiryEntry{File: leaf.crt}
+		crt, backups, err := renewLeaf(dir, leaf, ca, now, time.Duration(within), opts.Rekey, suffix)
+		switch {
+		case err != nil:
+			entry.Status = VerifyError
+			entry.Error = err.Error()
+		case backups == nil:
+			entry.Status = ExpiryOK
+		default:
+			entry.Status = StatusRenewed
+			entry.Backups = backups
+		}
+		if crt != nil {
+			entry.Subject = crt.Subject.CommonName
+			entry.NotAfter = crt.NotAfter
+		}
+		report.Certificates = append(report.Certificates, entry)
+	}
+	return report, nil
+}
+
+// renewLeaf renews the certificate if it expires within the threshold and
+// returns the current certificate and the backups of replaced files, which
+// are nil if nothing was renewed.
+func renewLeaf(dir string, leaf leafFile, ca *Certificate, now time.Time, within time.Duration, rekey bool, suffix string) (*x509.Certificate, []string, error) {
+	// certificates signed for requests of other machines have no key here
+	keyFile := leaf.key
+	if _, err := os.Stat(filepath.Join(dir, keyFile)); errors.Is(err, fs.ErrNotExist) {
+		keyFile = ""
+	}
+	c, err := readCertificate(dir, leaf.crt, keyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	old, err := c.GetCertificate()
+	if err != nil {
+		return nil, nil, err
+	}
+	if expiryStatus(old, now, within) == ExpiryOK {
+		return old, nil, nil
+	}
+
+	caCrt, err := ca.GetCertificate()
+	if err != nil {
+		return old, nil, err
+	}
+	// expired certificates do not verify, so only the signature is checked
+	if err := old.CheckSignatureFrom(caCrt); err != nil {
+		return old, nil, fmt.Errorf("certificate was not issued by CA %q: %w", caCrt.Subject.CommonName, err)
+	}
+	if keyFile != "" {
+		if err := c.keyMatches(old.PublicKey); err != nil {
+			return old, nil, err
+		}
+	}
+
+	pub := old.PublicKey
+	var key crypto.Signer
+	if rekey {
+		keyType, err := keyTypeOf(old.PublicKey)
+		if err != nil {
+			return old, nil, err
+		}
+		if key, err = generateKey(keyType); err != nil {
+			return old, nil, err
+		}
+		pub = key.Public()
+	}
+
+	template := &x509.Certificate{
+		RawSubject:     old.RawSubject,
+		DNSNames:       old.DNSNames,
+		IPAddresses:    old.IPAddresses,
+		EmailAddresses: old.EmailAddresses,
+		URIs:           old.URIs,
+		KeyUsage:       old.KeyUsage,
+		ExtKeyUsage:    old.ExtKeyUsage,
+	}
+	profile := Profile{Validity: Duration(old.NotAfter.Sub(old.NotBefore))}
+	crtPem, err := issueCertificate(ca, template, pub, profile, 0)
+	if err != nil {
+		return old, nil, err
+	}
+	// the lifetime is capped by the CA, a certificate expiring with a CA
+	// close to its own expiry would be renewed again by every run
+	next, err := (&Certificate{Certificate: string(crtPem)}).GetCertificate()
+	if err != nil {
+		return old, nil, err
+	}
+	if next.NotAfter.Equal(caCrt.NotAfter) && expiryStatus(next, now, within) != ExpiryOK {
+		return old, nil, fmt.Errorf("not renewed, CA %q expires at %s, renew the CA first", caCrt.Subject.CommonName, caCrt.NotAfter.Format(time.RFC3339))
+	}
+
+	files := map[string][]byte{leaf.crt: crtPem}
+	if rekey {
+		if files[leaf.key], err = encodePrivateKey(key); err != nil {
+			return old, nil, err
+		}
+		if leaf.csr != "" {
+			if _, err := os.Stat(filepath.Join(dir, leaf.csr)); err == nil {
+				if files[leaf.csr], err = createRequest(old, key); err != nil {
+					return old, nil, err
+				}
+			}
+		}
+	}
+
+	var backups []string
+	for _, file := range []string{leaf.crt, leaf.key, leaf.csr} {
+		content, ok := files[file]
+		if !ok {
+			continue
+		}
+		// the new file keeps the permissions of the one it replaces, keys
+		// created by --rekey may not replace any
+		info, err := os.Stat(filepath.Join(dir, file))
+		if errors.Is(err, fs.ErrNotExist) && file == leaf.key {
+			if err := createFile(dir, file, string(content)); err != nil {
+				return old, backups, err
+			}
+			continue
+		}
+		if err != nil {
+			return old, backups, err
+		}
+		backup := file + suffix
+		if err := os.Rename(filepath.Join(dir, file), filepath.Join(dir, backup)); err != nil {
+			return old, backups, fmt.Errorf("failed to back up %s: %w", file, err)
+		}
+		backups = append(backups, backup)
+		if err := createFile(dir, file, string(content)); err != nil {
+			return old, backups, err
+		}
+		if err := os.Chmod(filepath.Join(dir, file), info.Mode().Perm()); err != nil {
+			return old, backups, fmt.Errorf("failed to set permissions of %s: %w", file, err)
+		}
+	}
+
+	if rekey {
+		keyFile = leaf.key
+	}
+	renewed, err := readCertificate(dir, leaf.crt, keyFile)
+	if err != nil {
+		return old, backups, err
+	}
+	crt, err := verifyIssued(renewed, ca, leaf.usage)
+	if err != nil {
+		return old, backups, fmt.Errorf("failed to verify renewed certificate: %w", err)
+	}
+	return crt, backups, nil
+}
+
+// createRequest creates a certificate request for the subject and SANs of
+// crt signed by key.
+func createRequest(crt *x509.Certificate, key crypto.Signer) ([]byte, error) {
+	template := &x509.CertificateRequest{
+		RawSubject:     crt.RawSubject,
+		DNSNames:       crt.DNSNames,
+		IPAddresses:    crt.IPAddresses,
+		EmailAddresses: crt.EmailAddresses,
+		URIs:           crt.URIs,
+	}
+	csrBytes, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		return nil, fmt.Errorf("could not create certificate request: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE REQUEST",
+		Bytes: csrBytes,
+	}), nil
+}
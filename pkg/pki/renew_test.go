@@ -0,0 +1,328 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package pki
+
+import (
+	"bytes"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckExpiry(t *testing.T) {
+	dir := t.TempDir()
+	profile := Profile{KeyType: KeyTypeECDSAP256}
+	template := Template{CA: profile, Server: profile, Client: profile}
+	if _, err := HandleInit(dir, "unsafe.aurae.io", InitOptions{Users: []string{"nova"}, Template: template}); err != nil {
+		t.Fatalf("could not initialize pki: %s", err)
+	}
+
+	report, err := CheckExpiry(dir, DefaultRenewWithin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.OK() || len(report.Certificates) != 3 {
+		t.Fatalf("want CA, server and client certificate to be ok, got %+v", report.Certificates)
+	}
+
+	// leaf certificates are valid for 365 days
+	report, err = CheckExpiry(dir, Duration(400*24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.OK() {
+		t.Fatalf("want server and client certificate to expire, got %+v", report.Certificates)
+	}
+	for _, e := range report.Certificates {
+		want := ExpiryExpiring
+		if e.File == "ca.crt" {
+			want = ExpiryOK
+		}
+		if e.Status != want {
+			t.Fatalf("%s: want status %s, got %s", e.File, want, e.Status)
+		}
+	}
+
+	if _, err := CheckExpiry(filepath.Join(dir, "missing"), DefaultRenewWithin); err == nil {
+		t.Fatal("want error for missing directory, got no error")
+	}
+}
+
+func TestExpiryStatus(t *testing.T) {
+	now := time.Now()
+	crt := &x509.Certificate{NotAfter: now.Add(10 * 24 * time.Hour)}
+	ts := []struct {
+		now    time.Time
+		within time.Duration
+		want   string
+	}{
+		{now: now, within: 5 * 24 * time.Hour, want: ExpiryOK},
+		{now: now, within: 30 * 24 * time.Hour, want: ExpiryExpiring},
+		{now: now.Add(11 * 24 * time.Hour), within: 0, want: ExpiryExpired},
+	}
+	for _, tt := range ts {
+		if got := expiryStatus(crt, tt.now, tt.within); got != tt.want {
+			t.Fatalf("now %s, within %s: want %s, got %s", tt.now, tt.within, tt.want, got)
+		}
+	}
+}
+
+func TestHandleRenew(t *testing.T) {
+	within := Duration(400 * 24 * time.Hour)
+	profile := Profile{KeyType: KeyTypeECDSAP256}
+	template := Template{CA: profile, Server: profile, Client: profile}
+	setup := func(t *testing.T) string {
+		dir := t.TempDir()
+		if _, err := HandleInit(dir, "unsafe.aurae.io", InitOptions{Users: []string{"nova"}, Template: template}); err != nil {
+			t.Fatalf("could not initialize pki: %s", err)
+		}
+		return dir
+	}
+	read := func(t *testing.T, dir, file string) []byte {
+		b, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	t.Run("nothing to renew", func(t *testing.T) {
+		dir := setup(t)
+		crt := read(t, dir, "_signed.server.crt")
+		report, err := HandleRenew(dir, DefaultRenewWithin, RenewOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range report.Certificates {
+			if e.Status != ExpiryOK || len(e.Backups) != 0 {
+				t.Fatalf("%s: want ok without backups, got %+v", e.File, e)
+			}
+		}
+		if !bytes.Equal(crt, read(t, dir, "_signed.server.crt")) {
+			t.Fatal("want server certificate to be kept")
+		}
+	})
+
+	t.Run("same key", func(t *testing.T) {
+		dir := setup(t)
+		oldCrt := read(t, dir, "_signed.client.nova.crt")
+		oldKey := read(t, dir, "client.nova.key")
+		oldCsr := read(t, dir, "client.nova.csr")
+
+		report, err := HandleRenew(dir, within, RenewOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !report.OK() || len(report.Certificates) != 2 {
+			t.Fatalf("want server and client certificate renewed, got %+v", report.Certificates)
+		}
+		for _, e := range report.Certificates {
+			if e.Status != StatusRenewed || len(e.Backups) != 1 {
+				t.Fatalf("%s: want renewed with backup of the certificate, got %+v", e.File, e)
+			}
+		}
+
+		if bytes.Equal(oldCrt, read(t, dir, "_signed.client.nova.crt")) {
+			t.Fatal("want client certificate to be renewed")
+		}
+		if !bytes.Equal(oldKey, read(t, dir, "client.nova.key")) || !bytes.Equal(oldCsr, read(t, dir, "client.nova.csr")) {
+			t.Fatal("want client key and request to be kept")
+		}
+		if !bytes.Equal(oldCrt, read(t, dir, report.Certificates[1].Backups[0])) {
+			t.Fatal("want backup of the old client certificate")
+		}
+
+		old, err := (&Certificate{Certificate: string(oldCrt)}).GetCertificate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		renewed, err := (&Certificate{Certificate: string(read(t, dir, "_signed.client.nova.crt"))}).GetCertificate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(old.RawSubject, renewed.RawSubject) || len(renewed.DNSNames) != 1 || renewed.DNSNames[0] != old.DNSNames[0] {
+			t.Fatalf("want subject and SANs of the old certificate, got %s %v", renewed.Subject, renewed.DNSNames)
+		}
+		if old.SerialNumber.Cmp(renewed.SerialNumber) == 0 {
+			t.Fatal("want a new serial number")
+		}
+
+		info, err := os.Stat(filepath.Join(dir, "_signed.client.nova.crt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0o644 {
+			t.Fatalf("want permissions of the old certificate, got %s", info.Mode())
+		}
+
+		verify, err := VerifyDirectory(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !verify.OK() {
+			t.Fatalf("want renewed pki to verify, got %+v", verify.Results)
+		}
+	})
+
+	t.Run("rekey", func(t *testing.T) {
+		dir := setup(t)
+		oldKey := read(t, dir, "client.nova.key")
+		oldCsr := read(t, dir, "client.nova.csr")
+
+		report, err := HandleRenew(dir, within, RenewOptions{Rekey: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !report.OK() {
+			t.Fatalf("want certificates renewed, got %+v", report.Certificates)
+		}
+		if len(report.Certificates[0].Backups) != 2 || len(report.Certificates[1].Backups) != 3 {
+			t.Fatalf("want backups of certificates, keys and requests, got %+v", report.Certificates)
+		}
+		if bytes.Equal(oldKey, read(t, dir, "client.nova.key")) || bytes.Equal(oldCsr, read(t, dir, "client.nova.csr")) {
+			t.Fatal("want new client key and request")
+		}
+
+		verify, err := VerifyDirectory(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !verify.OK() {
+			t.Fatalf("want renewed pki to verify, got %+v", verify.Results)
+		}
+	})
+
+	t.Run("without key", func(t *testing.T) {
+		dir := setup(t)
+		// a certificate signed by 'ae pki sign' for a request of another
+		// machine
+		if err := os.Remove(filepath.Join(dir, "client.nova.key")); err != nil {
+			t.Fatal(err)
+		}
+		oldCrt := read(t, dir, "_signed.client.nova.crt")
+
+		report, err := HandleRenew(dir, within, RenewOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !report.OK() || report.Certificates[1].Status != StatusRenewed {
+			t.Fatalf("want client certificate renewed, got %+v", report.Certificates)
+		}
+
+		old, err := (&Certificate{Certificate: string(oldCrt)}).GetCertificate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		renewed, err := (&Certificate{Certificate: string(read(t, dir, "_signed.client.nova.crt"))}).GetCertificate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !old.PublicKey.(publicKey).Equal(renewed.PublicKey) {
+			t.Fatal("want renewed certificate for the public key of the old one")
+		}
+	})
+
+	t.Run("capped by CA", func(t *testing.T) {
+		dir := t.TempDir()
+		short := Template{CA: Profile{KeyType: KeyTypeECDSAP256, Validity: Duration(100 * 24 * time.Hour)}, Server: profile, Client: profile}
+		if _, err := HandleInit(dir, "unsafe.aurae.io", InitOptions{Users: []string{"nova"}, Template: short}); err != nil {
+			t.Fatalf("could not initialize pki: %s", err)
+		}
+		crt := read(t, dir, "_signed.server.crt")
+
+		report, err := HandleRenew(dir, within, RenewOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range report.Certificates {
+			if e.Status != VerifyError || len(e.Backups) != 0 || !strings.Contains(e.Error, "renew the CA") {
+				t.Fatalf("%s: want CA expiry reported without renewing, got %+v", e.File, e)
+			}
+		}
+		if !bytes.Equal(crt, read(t, dir, "_signed.server.crt")) {
+			t.Fatal("want server certificate to be kept")
+		}
+	})
+
+	t.Run("intermediate", func(t *testing.T) {
+		root := setup(t)
+		rootCA, err := LoadCA(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		caDir := t.TempDir()
+		ca, err := HandleCreateIntermediateCA(caDir, rootCA, "cluster-1.unsafe.aurae.io", profile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dir := t.TempDir()
+		if _, err := HandleCreateServerCertificate(dir, ca, "unsafe.aurae.io", nil, nil, profile); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := HandleRenew(dir, within, RenewOptions{}); err == nil {
+			t.Fatal("want error for directory without CA, got no error")
+		}
+		report, err := HandleRenew(dir, within, RenewOptions{CADirectory: caDir})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !report.OK() || len(report.Certificates) != 1 || report.Certificates[0].Status != StatusRenewed {
+			t.Fatalf("want server certificate renewed, got %+v", report.Certificates)
+		}
+		srv, err := readCertificate(dir, "_signed.server.crt", "server.key")
+		if err != nil {
+			t.Fatal(err)
+		}
+		chain, err := srv.GetChain()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(chain) != 2 {
+			t.Fatalf("want server certificate bundled with the intermediate, got %d certificates", len(chain))
+		}
+		if _, err := verifyIssued(srv, ca, x509.ExtKeyUsageServerAuth); err != nil {
+			t.Fatal(err)
+		}
+
+		// the root did not issue the server certificate
+		report, err = HandleRenew(dir, within, RenewOptions{CADirectory: root})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.OK() || report.Certificates[0].Status != VerifyError {
+			t.Fatalf("want error for certificate of another CA, got %+v", report.Certificates)
+		}
+	})
+}
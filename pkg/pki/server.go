@@ -0,0 +1,117 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package pki
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"time"
+)
+
+// DefaultServerValidity is the validity of server certificates issued by
+// HandleCreateServerCertificate unless a different one is requested.
+const DefaultServerValidity = 365 * 24 * time.Hour
+
+// ServerName returns the default name of the auraed server in domain, which
+// is the name clients verify unless configured otherwise.
+func ServerName(domain string) string {
+	return fmt.Sprintf("server.%s", domain)
+}
+
+// HandleCreateServerCertificate issues a certificate and key for auraed
+// signed by the CA. The certificate is valid for the given DNS names and IP
+// addresses, where the DNS names default to ServerName(domain). If path is
+// set, the certificate and key are written to _signed.server.crt and
+// server.key in path, the files auraed loads by default.
+func HandleCreateServerCertificate(path string, ca *Certificate, domain string, dnsNames []string, ips []net.IP, profile Profile) (*Certificate, error) {
+	if len(dnsNames) == 0 {
+		dnsNames = []string{ServerName(domain)}
+	}
+
+	crtPem, keyPem, err := createServerCertificate(ca, domain, dnsNames, ips, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	crt := &Certificate{
+		Certificate: string(crtPem),
+		PrivateKey:  string(keyPem),
+	}
+
+	if path != "" {
+		err = crt.WriteCertificateToFile(path, "_signed.server.crt")
+		if err != nil {
+			return crt, err
+		}
+		err = crt.WritePrivateKeyToFile(path, "server.key")
+		if err != nil {
+			return crt, err
+		}
+		if crt, err = readCertificate(path, "_signed.server.crt", "server.key"); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := verifyIssued(crt, ca, x509.ExtKeyUsageServerAuth); err != nil {
+		return crt, fmt.Errorf("failed to verify server certificate: %w", err)
+	}
+
+	return crt, nil
+}
+
+func createServerCertificate(ca *Certificate, domain string, dnsNames []string, ips []net.IP, profile Profile) ([]byte, []byte, error) {
+	priv, err := generateKey(profile.KeyType.orDefault(DefaultKeyType))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		Subject:     profile.name(ServerName(domain)),
+		DNSNames:    dnsNames,
+		IPAddresses: ips,
+	}
+	if err := profile.applyLeaf(template, priv.Public(), x509.ExtKeyUsageServerAuth); err != nil {
+		return nil, nil, err
+	}
+
+	crtPem, err := issueCertificate(ca, template, priv.Public(), profile, DefaultServerValidity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyPem, err := encodePrivateKey(priv)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return crtPem, keyPem, nil
+}
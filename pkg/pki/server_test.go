@@ -0,0 +1,140 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package pki
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCreateServerCertificate(t *testing.T) {
+	t.Run("default SAN with local files", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestCA(t, dir, 24*time.Hour*9999)
+		ca, err := LoadCA(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := HandleCreateServerCertificate(dir, ca, "unsafe.aurae.io", nil, nil, Profile{}); err != nil {
+			t.Fatalf("could not create server certificate: %s", err)
+		}
+
+		crt, err := tls.LoadX509KeyPair(filepath.Join(dir, "_signed.server.crt"), filepath.Join(dir, "server.key"))
+		if err != nil {
+			t.Fatalf("could not load server certificate: %s", err)
+		}
+		leaf, err := x509.ParseCertificate(crt.Certificate[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		caCrt, _ := ca.GetCertificate()
+		roots := x509.NewCertPool()
+		roots.AddCert(caCrt)
+		if _, err := leaf.Verify(x509.VerifyOptions{Roots: roots, DNSName: "server.unsafe.aurae.io"}); err != nil {
+			t.Errorf("server certificate does not verify for server.unsafe.aurae.io: %s", err)
+		}
+		if leaf.IsCA || leaf.KeyUsage != x509.KeyUsageDigitalSignature|x509.KeyUsageKeyEncipherment {
+			t.Errorf("unexpected key usage %v for server certificate", leaf.KeyUsage)
+		}
+	})
+
+	t.Run("custom SANs", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestCA(t, dir, time.Hour)
+		ca, _ := LoadCA(dir)
+
+		srv, err := HandleCreateServerCertificate("", ca, "unsafe.aurae.io", []string{"node-1.unsafe.aurae.io"}, []net.IP{net.ParseIP("10.0.0.1")}, Profile{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		leaf, _ := srv.GetCertificate()
+		if len(leaf.DNSNames) != 1 || leaf.DNSNames[0] != "node-1.unsafe.aurae.io" {
+			t.Errorf("want only the given DNS name, got %v", leaf.DNSNames)
+		}
+		if len(leaf.IPAddresses) != 1 || !leaf.IPAddresses[0].Equal(net.ParseIP("10.0.0.1")) {
+			t.Errorf("want the given IP address, got %v", leaf.IPAddresses)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "server.key")); !os.IsNotExist(err) {
+			t.Errorf("want no files without a path, got %v", err)
+		}
+	})
+
+	t.Run("mTLS handshake with signed client certificate", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestCA(t, dir, time.Hour)
+		ca, _ := LoadCA(dir)
+		caCrt, _ := ca.GetCertificate()
+		pool := x509.NewCertPool()
+		pool.AddCert(caCrt)
+
+		srv, err := HandleCreateServerCertificate("", ca, "unsafe.aurae.io", nil, nil, Profile{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, err := HandleCreateClientCSR("", "unsafe.aurae.io", "nova", Profile{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		client, err := HandleSignClientCSR("", ca, req, Profile{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		srvPair, err := tls.X509KeyPair([]byte(srv.Certificate), []byte(srv.PrivateKey))
+		if err != nil {
+			t.Fatal(err)
+		}
+		clientPair, err := tls.X509KeyPair([]byte(client.Certificate), []byte(req.PrivateKey))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sc, cc := net.Pipe()
+		defer sc.Close()
+		defer cc.Close()
+		errs := make(chan error, 1)
+		go func() {
+			errs <- tls.Server(sc, &tls.Config{Certificates: []tls.Certificate{srvPair}, ClientCAs: pool, ClientAuth: tls.RequireAndVerifyClientCert}).Handshake()
+		}()
+		if err := tls.Client(cc, &tls.Config{Certificates: []tls.Certificate{clientPair}, RootCAs: pool, ServerName: "server.unsafe.aurae.io"}).Handshake(); err != nil {
+			t.Fatalf("client handshake failed: %s", err)
+		}
+		if err := <-errs; err != nil {
+			t.Fatalf("server handshake failed: %s", err)
+		}
+	})
+}
@@ -0,0 +1,201 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package pki
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultClientValidity is the validity of client certificates signed by
+// HandleSignClientCSR unless a different one is requested.
+const DefaultClientValidity = 365 * 24 * time.Hour
+
+// LoadCA reads the CA certificate and private key stored as ca.crt and ca.key
+// in dir, the layout written by HandleCreateAuraeRootCA and
+// HandleCreateIntermediateCA. For intermediate CAs ca.crt holds the chain up
+// to the root.
+func LoadCA(dir string) (*Certificate, error) {
+	ca, err := readCertificate(dir, "ca.crt", "ca.key")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := verifyCA(ca); err != nil {
+		return nil, fmt.Errorf("invalid CA: %w", err)
+	}
+	return ca, nil
+}
+
+// ReadClientCSR reads a PEM encoded certificate request of the given user.
+func ReadClientCSR(file, user string) (*CertificateRequest, error) {
+	csrPem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate request: %w", err)
+	}
+
+	csr := &CertificateRequest{
+		CSR:  string(csrPem),
+		User: user,
+	}
+	if _, err := csr.GetCsr(); err != nil {
+		return nil, err
+	}
+	return csr, nil
+}
+
+// HandleSignClientCSR issues a client certificate for the certificate request
+// signed by the CA. If path is set, the certificate is written to
+// _signed.client.<user>.crt in path.
+func HandleSignClientCSR(path string, ca *Certificate, csr *CertificateRequest, profile Profile) (*Certificate, error) {
+	if err := validateUser(csr.User); err != nil {
+		return nil, err
+	}
+
+	crtPem, err := signClientCSR(ca, csr, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	crt := &Certificate{
+		Certificate: string(crtPem),
+	}
+
+	file := fmt.Sprintf("_signed.client.%s.crt", csr.User)
+	if path != "" {
+		err = crt.WriteCertificateToFile(path, file)
+		if err != nil {
+			return crt, err
+		}
+		if crt, err = readCertificate(path, file, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := verifySigned(crt, ca, csr); err != nil {
+		return crt, fmt.Errorf("failed to verify client certificate: %w", err)
+	}
+
+	return crt, nil
+}
+
+// validateUser checks that the user can be used in file names.
+func validateUser(user string) error {
+	if user == "" || strings.ContainsAny(user, `/\`) {
+		return fmt.Errorf("invalid user %q", user)
+	}
+	return nil
+}
+
+func signClientCSR(ca *Certificate, csr *CertificateRequest, profile Profile) ([]byte, error) {
+	req, err := csr.GetCsr()
+	if err != nil {
+		return nil, err
+	}
+	if err := req.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("invalid certificate request signature: %w", err)
+	}
+
+	template := &x509.Certificate{
+		Subject:        req.Subject,
+		DNSNames:       req.DNSNames,
+		EmailAddresses: req.EmailAddresses,
+		IPAddresses:    req.IPAddresses,
+		URIs:           req.URIs,
+	}
+	if err := profile.applyLeaf(template, req.PublicKey, x509.ExtKeyUsageClientAuth); err != nil {
+		return nil, err
+	}
+
+	return issueCertificate(ca, template, req.PublicKey, profile, DefaultClientValidity)
+}
+
+// issueCertificate signs a certificate for the public key with the CA. The
+// serial number and validity of template are set here, where the validity
+// of the profile defaults to def. If the CA is an intermediate, the returned
+// PEM contains the certificate followed by the intermediate CAs, so peers
+// that only trust the root can verify it.
+func issueCertificate(ca *Certificate, template *x509.Certificate, pub any, profile Profile, def time.Duration) ([]byte, error) {
+	caChain, err := ca.GetChain()
+	if err != nil {
+		return nil, err
+	}
+	caCrt := caChain[0]
+	caKey, err := ca.GetPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	notAfter, err := profile.notAfter(now, def)
+	if err != nil {
+		return nil, err
+	}
+	// a certificate can not be valid for longer than the CA signing it
+	if notAfter.After(caCrt.NotAfter) {
+		notAfter = caCrt.NotAfter
+	}
+
+	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+
+	template.SerialNumber = serialNumber
+	template.NotBefore = now
+	template.NotAfter = notAfter
+	template.BasicConstraintsValid = true
+
+	crtBytes, err := x509.CreateCertificate(rand.Reader, template, caCrt, pub, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+
+	crtPem := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: crtBytes,
+	})
+	for _, crt := range caChain {
+		if !isSelfSigned(crt) {
+			crtPem = append(crtPem, pem.EncodeToMemory(&pem.Block{
+				Type:  "CERTIFICATE",
+				Bytes: crt.Raw,
+			})...)
+		}
+	}
+	return crtPem, nil
+}
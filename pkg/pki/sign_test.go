@@ -0,0 +1,158 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package pki
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestCA writes a CA valid for the given duration to dir.
+func writeTestCA(t *testing.T, dir string, validity time.Duration) {
+	t.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := x509.Certificate{
+		Subject:               pkix.Name{CommonName: "unsafe.aurae.io"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(validity),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		SerialNumber:          big.NewInt(1),
+	}
+	crtBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca := &Certificate{
+		Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: crtBytes})),
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)})),
+	}
+	if err := ca.WriteCertificateToFile(dir, "ca.crt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ca.WritePrivateKeyToFile(dir, "ca.key"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSignClientCSR(t *testing.T) {
+	t.Run("sign csr with local files", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestCA(t, dir, 24*time.Hour*9999)
+
+		ca, err := LoadCA(dir)
+		if err != nil {
+			t.Fatalf("could not load CA: %s", err)
+		}
+		if _, err := HandleCreateClientCSR(dir, "unsafe.aurae.io", "nova", Profile{}); err != nil {
+			t.Fatalf("could not create csr: %s", err)
+		}
+		csr, err := ReadClientCSR(filepath.Join(dir, "client.nova.csr"), "nova")
+		if err != nil {
+			t.Fatalf("could not read csr: %s", err)
+		}
+
+		if _, err := HandleSignClientCSR(dir, ca, csr, Profile{}); err != nil {
+			t.Fatalf("could not sign csr: %s", err)
+		}
+
+		crtPem, err := os.ReadFile(filepath.Join(dir, "_signed.client.nova.crt"))
+		if err != nil {
+			t.Fatalf("could not read signed certificate: %s", err)
+		}
+		crt, err := (&Certificate{Certificate: string(crtPem)}).GetCertificate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		caCrt, _ := ca.GetCertificate()
+		roots := x509.NewCertPool()
+		roots.AddCert(caCrt)
+		if _, err := crt.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+			t.Errorf("signed certificate does not verify for client auth: %s", err)
+		}
+		if crt.Subject.CommonName != "nova.unsafe.aurae.io" || len(crt.DNSNames) != 1 || crt.DNSNames[0] != "nova.unsafe.aurae.io" {
+			t.Errorf("signed certificate does not contain subject of csr: %s %v", crt.Subject, crt.DNSNames)
+		}
+		if crt.IsCA || crt.KeyUsage != x509.KeyUsageDigitalSignature|x509.KeyUsageKeyEncipherment {
+			t.Errorf("unexpected key usage %v for client certificate", crt.KeyUsage)
+		}
+		if d := crt.NotAfter.Sub(crt.NotBefore); d != DefaultClientValidity {
+			t.Errorf("want validity of %s, got %s", DefaultClientValidity, d)
+		}
+	})
+
+	t.Run("validity is limited by the CA", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestCA(t, dir, time.Hour)
+
+		ca, err := LoadCA(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, err := HandleCreateClientCSR("", "unsafe.aurae.io", "nova", Profile{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		signed, err := HandleSignClientCSR("", ca, req, Profile{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		crt, _ := signed.GetCertificate()
+		caCrt, _ := ca.GetCertificate()
+		if !crt.NotAfter.Equal(caCrt.NotAfter) {
+			t.Errorf("want certificate to expire with the CA at %s, got %s", caCrt.NotAfter, crt.NotAfter)
+		}
+	})
+
+	t.Run("reject mismatching CA key", func(t *testing.T) {
+		dir, other := t.TempDir(), t.TempDir()
+		writeTestCA(t, dir, time.Hour)
+		writeTestCA(t, other, time.Hour)
+		if err := os.Rename(filepath.Join(other, "ca.key"), filepath.Join(dir, "ca.key")); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadCA(dir); err == nil {
+			t.Errorf("want error for mismatching CA key, got no error")
+		}
+	})
+}
@@ -0,0 +1,385 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package pki
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// publicKey is implemented by all public keys of the standard library.
+type publicKey interface {
+	Equal(crypto.PublicKey) bool
+}
+
+// keyMatches reports whether the private key of c belongs to pub.
+func (c *Certificate) keyMatches(pub crypto.PublicKey) error {
+	key, err := c.GetPrivateKey()
+	if err != nil {
+		return err
+	}
+	if !key.Public().(publicKey).Equal(pub) {
+		return errors.New("private key does not match certificate")
+	}
+	return nil
+}
+
+// isSelfSigned reports whether crt is a root certificate.
+func isSelfSigned(crt *x509.Certificate) bool {
+	return bytes.Equal(crt.RawSubject, crt.RawIssuer) && crt.CheckSignatureFrom(crt) == nil
+}
+
+// caPools splits the chain of a CA into its roots and intermediates.
+func caPools(chain []*x509.Certificate) (*x509.CertPool, *x509.CertPool, error) {
+	roots := x509.NewCertPool()
+	intermediates := x509.NewCertPool()
+	hasRoot := false
+	for _, crt := range chain {
+		if isSelfSigned(crt) {
+			roots.AddCert(crt)
+			hasRoot = true
+		} else {
+			intermediates.AddCert(crt)
+		}
+	}
+	// without a root the system roots would be used
+	if !hasRoot {
+		return nil, nil, errors.New("CA chain does not end with a root certificate")
+	}
+	return roots, intermediates, nil
+}
+
+// verifyCA checks that c is a CA certificate. The private key of c is
+// checked if c contains one. It must either be self-signed or be followed
+// by the chain of CAs that issued it, up to the root.
+func verifyCA(c *Certificate) (*x509.Certificate, error) {
+	chain, err := c.GetChain()
+	if err != nil {
+		return nil, err
+	}
+	crt := chain[0]
+	if !crt.IsCA {
+		return nil, fmt.Errorf("certificate %q is not a CA", crt.Subject.CommonName)
+	}
+	if crt.KeyUsage != 0 && crt.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return nil, fmt.Errorf("CA %q may not sign certificates", crt.Subject.CommonName)
+	}
+	if !isSelfSigned(crt) {
+		roots, intermediates, err := caPools(chain[1:])
+		if err != nil {
+			return nil, fmt.Errorf("CA %q is not self-signed: %w", crt.Subject.CommonName, err)
+		}
+		opts := x509.VerifyOptions{Roots: roots, Intermediates: intermediates, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}
+		if _, err := crt.Verify(opts); err != nil {
+			return nil, fmt.Errorf("invalid chain of CA %q: %w", crt.Subject.CommonName, err)
+		}
+	}
+	if c.PrivateKey != "" {
+		if err := c.keyMatches(crt.PublicKey); err != nil {
+			return nil, err
+		}
+	}
+	return crt, nil
+}
+
+// verifyIssued checks that c is valid for usage and was signed by ca. The
+// private key of c is checked if c contains one. Intermediate CAs following
+// the certificate in c are used to build the chain, but never as roots.
+func verifyIssued(c *Certificate, ca *Certificate, usage x509.ExtKeyUsage) (*x509.Certificate, error) {
+	chain, err := c.GetChain()
+	if err != nil {
+		return nil, err
+	}
+	crt := chain[0]
+	caChain, err := ca.GetChain()
+	if err != nil {
+		return nil, err
+	}
+
+	roots, intermediates, err := caPools(caChain)
+	if err != nil {
+		return nil, err
+	}
+	for _, ic := range chain[1:] {
+		if !isSelfSigned(ic) {
+			intermediates.AddCert(ic)
+		}
+	}
+	verified, err := crt.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, KeyUsages: []x509.ExtKeyUsage{usage}})
+	if err != nil {
+		return nil, err
+	}
+	if !issuedBy(verified, caChain[0]) {
+		return nil, fmt.Errorf("certificate was not issued by CA %q", caChain[0].Subject.CommonName)
+	}
+
+	if c.PrivateKey != "" {
+		if err := c.keyMatches(crt.PublicKey); err != nil {
+			return nil, err
+		}
+	}
+	return crt, nil
+}
+
+// issuedBy reports whether ca is part of any of the verified chains.
+func issuedBy(chains [][]*x509.Certificate, ca *x509.Certificate) bool {
+	for _, chain := range chains {
+		for _, crt := range chain[1:] {
+			if crt.Equal(ca) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// verifyRequest checks the signature of the certificate request and that
+// its private key matches, if it contains one.
+func verifyRequest(c *CertificateRequest) (*x509.CertificateRequest, error) {
+	csr, err := c.GetCsr()
+	if err != nil {
+		return nil, err
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("invalid certificate request signature: %w", err)
+	}
+	if c.PrivateKey != "" {
+		key, err := c.GetPrivateKey()
+		if err != nil {
+			return nil, err
+		}
+		if !key.Public().(publicKey).Equal(csr.PublicKey) {
+			return nil, errors.New("private key does not match certificate request")
+		}
+	}
+	return csr, nil
+}
+
+// readCertificate reads a certificate and, if keyFile is set, its private
+// key from path.
+func readCertificate(path, crtFile, keyFile string) (*Certificate, error) {
+	crtPem, err := os.ReadFile(filepath.Join(path, crtFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate: %w", err)
+	}
+	c := &Certificate{Certificate: string(crtPem)}
+	if keyFile != "" {
+		keyPem, err := os.ReadFile(filepath.Join(path, keyFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private key: %w", err)
+		}
+		c.PrivateKey = string(keyPem)
+	}
+	return c, nil
+}
+
+// readRequest reads the certificate request and private key of user
+// written by HandleCreateClientCSR from path.
+func readRequest(path, user string) (*CertificateRequest, error) {
+	csrPem, err := os.ReadFile(filepath.Join(path, fmt.Sprintf("client.%s.csr", user)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate request: %w", err)
+	}
+	keyPem, err := os.ReadFile(filepath.Join(path, fmt.Sprintf("client.%s.key", user)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+	return &CertificateRequest{CSR: string(csrPem), PrivateKey: string(keyPem), User: user}, nil
+}
+
+// verifySigned checks that c is a client certificate signed by ca for the
+// key of the certificate request.
+func verifySigned(c *Certificate, ca *Certificate, req *CertificateRequest) error {
+	crt, err := verifyIssued(c, ca, x509.ExtKeyUsageClientAuth)
+	if err != nil {
+		return err
+	}
+	csr, err := req.GetCsr()
+	if err != nil {
+		return err
+	}
+	if !crt.PublicKey.(publicKey).Equal(csr.PublicKey) {
+		return errors.New("certificate does not match certificate request")
+	}
+	return nil
+}
+
+const (
+	VerifyOK    = "ok"
+	VerifyError = "error"
+)
+
+// VerifyResult is the outcome of verifying a single file of a PKI
+// directory.
+type VerifyResult struct {
+	File     string     `json:"file" yaml:"file"`
+	Subject  string     `json:"subject,omitempty" yaml:"subject,omitempty"`
+	NotAfter *time.Time `json:"notAfter,omitempty" yaml:"notAfter,omitempty"`
+	Status   string     `json:"status" yaml:"status"`
+	Error    string     `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type VerifyReport struct {
+	Directory string         `json:"directory" yaml:"directory"`
+	Results   []VerifyResult `json:"results" yaml:"results"`
+}
+
+// OK reports whether all files of the directory are valid.
+func (r *VerifyReport) OK() bool {
+	for _, res := range r.Results {
+		if res.Status != VerifyOK {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *VerifyReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "PKI in %s\n", r.Directory)
+	w := tabwriter.NewWriter(&b, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "FILE\tSUBJECT\tEXPIRES\tSTATUS")
+	for _, res := range r.Results {
+		expires := ""
+		if res.NotAfter != nil {
+			expires = res.NotAfter.Format(time.RFC3339)
+		}
+		status := res.Status
+		if res.Error != "" {
+			status = fmt.Sprintf("%s: %s", res.Status, res.Error)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", res.File, res.Subject, expires, status)
+	}
+	w.Flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (r *VerifyReport) add(file string, crt *x509.Certificate, err error) {
+	res := VerifyResult{File: file, Status: VerifyOK}
+	if crt != nil {
+		res.Subject = crt.Subject.CommonName
+		res.NotAfter = &crt.NotAfter
+	}
+	if err != nil {
+		res.Status = VerifyError
+		res.Error = err.Error()
+	}
+	r.Results = append(r.Results, res)
+}
+
+// VerifyDirectory verifies the PKI in dir as laid out by HandleInit: the
+// CA and its key, the server certificate and key, and the signed client
+// certificates, requests and keys of every user. Certificates must chain
+// to the CA, be valid for their extended key usage and match their keys.
+// The CA key is only checked if present, client directories lack it.
+//
+// An error is only returned if dir can not be read; invalid files are
+// reported in the results.
+func VerifyDirectory(dir string) (*VerifyReport, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pki directory: %w", err)
+	}
+
+	report := &VerifyReport{Directory: dir}
+
+	// client and node directories hold the CA certificate without its key
+	caKey := "ca.key"
+	if _, err := os.Stat(filepath.Join(dir, caKey)); errors.Is(err, fs.ErrNotExist) {
+		caKey = ""
+	}
+	ca, err := readCertificate(dir, "ca.crt", caKey)
+	var caCrt *x509.Certificate
+	if err == nil {
+		caCrt, err = verifyCA(ca)
+	}
+	report.add("ca.crt", caCrt, err)
+	if err != nil {
+		// nothing else can be verified without a valid CA
+		return report, nil
+	}
+
+	var users []string
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, "_signed.client.") && strings.HasSuffix(name, ".crt") {
+			users = append(users, strings.TrimSuffix(strings.TrimPrefix(name, "_signed.client."), ".crt"))
+		}
+	}
+	sort.Strings(users)
+
+	if _, err := os.Stat(filepath.Join(dir, "_signed.server.crt")); !errors.Is(err, fs.ErrNotExist) {
+		srv, err := readCertificate(dir, "_signed.server.crt", "server.key")
+		var crt *x509.Certificate
+		if err == nil {
+			crt, err = verifyIssued(srv, ca, x509.ExtKeyUsageServerAuth)
+		}
+		report.add("_signed.server.crt", crt, err)
+	}
+
+	for _, user := range users {
+		crtFile := fmt.Sprintf("_signed.client.%s.crt", user)
+		c, err := readCertificate(dir, crtFile, fmt.Sprintf("client.%s.key", user))
+		var crt *x509.Certificate
+		if err == nil {
+			crt, err = verifyIssued(c, ca, x509.ExtKeyUsageClientAuth)
+		}
+		report.add(crtFile, crt, err)
+
+		csrFile := fmt.Sprintf("client.%s.csr", user)
+		csrPem, err := os.ReadFile(filepath.Join(dir, csrFile))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err == nil {
+			// without a readable certificate and key only the signature of
+			// the request is verified, the missing file is reported above
+			req := &CertificateRequest{CSR: string(csrPem)}
+			if c != nil {
+				req.PrivateKey = c.GetPrivateKeyAsString()
+			}
+			_, err = verifyRequest(req)
+		}
+		report.add(csrFile, nil, err)
+	}
+
+	return report, nil
+}
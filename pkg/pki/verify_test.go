@@ -0,0 +1,167 @@
+/* -------------------------------------------------------------------------- *\
+ *             Apache 2.0 License Copyright © 2022 The Aurae Authors          *
+ *                                                                            *
+ *                +--------------------------------------------+              *
+ *                |   █████╗ ██╗   ██╗██████╗  █████╗ ███████╗ |              *
+ *                |  ██╔══██╗██║   ██║██╔══██╗██╔══██╗██╔════╝ |              *
+ *                |  ███████║██║   ██║██████╔╝███████║█████╗   |              *
+ *                |  ██╔══██║██║   ██║██╔══██╗██╔══██║██╔══╝   |              *
+ *                |  ██║  ██║╚██████╔╝██║  ██║██║  ██║███████╗ |              *
+ *                |  ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝╚══════╝ |              *
+ *                +--------------------------------------------+              *
+ *                                                                            *
+ *                         Distributed Systems Runtime                        *
+ *                                                                            *
+ * -------------------------------------------------------------------------- *
+ *                                                                            *
+ *   Licensed under the Apache License, Version 2.0 (the "License");          *
+ *   you may not use this file except in compliance with the License.         *
+ *   You may obtain a copy of the License at                                  *
+ *                                                                            *
+ *       http://www.apache.org/licenses/LICENSE-2.0                           *
+ *                                                                            *
+ *   Unless required by applicable law or agreed to in writing, software      *
+ *   distributed under the License is distributed on an "AS IS" BASIS,        *
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ *   See the License for the specific language governing permissions and      *
+ *   limitations under the License.                                           *
+ *                                                                            *
+\* -------------------------------------------------------------------------- */
+
+package pki
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHandleCreateAuraeRootCALoads(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := HandleCreateAuraeRootCA(dir, "unsafe.aurae.io", Profile{}); err != nil {
+		t.Fatalf("could not create CA: %s", err)
+	}
+	if _, err := LoadCA(dir); err != nil {
+		t.Fatalf("could not load created CA: %s", err)
+	}
+}
+
+func TestVerifyDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := HandleInit(dir, "unsafe.aurae.io", InitOptions{Users: []string{"nova", "ops"}}); err != nil {
+		t.Fatalf("could not initialize pki: %s", err)
+	}
+
+	report, err := VerifyDirectory(dir)
+	if err != nil {
+		t.Fatalf("could not verify pki: %s", err)
+	}
+	if !report.OK() || len(report.Results) != 6 {
+		t.Fatalf("want 4 valid certificates and 2 requests, got %+v", report.Results)
+	}
+
+	// a client key that does not belong to the certificate
+	nova, err := os.ReadFile(filepath.Join(dir, "client.nova.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "client.ops.key"), nova, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// a client certificate used as server certificate
+	crt, err := os.ReadFile(filepath.Join(dir, "_signed.client.nova.crt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "_signed.server.crt"), crt, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err = VerifyDirectory(dir)
+	if err != nil {
+		t.Fatalf("could not verify pki: %s", err)
+	}
+	if report.OK() {
+		t.Fatal("want invalid pki, got ok")
+	}
+	invalid := map[string]string{}
+	for _, res := range report.Results {
+		if res.Status != VerifyOK {
+			invalid[res.File] = res.Error
+		}
+	}
+	if !strings.Contains(invalid["_signed.server.crt"], "key usage") {
+		t.Errorf("want key usage error for server certificate, got %q", invalid["_signed.server.crt"])
+	}
+	if !strings.Contains(invalid["_signed.client.ops.crt"], "does not match") {
+		t.Errorf("want key mismatch for ops, got %q", invalid["_signed.client.ops.crt"])
+	}
+	if !strings.Contains(invalid["client.ops.csr"], "does not match") {
+		t.Errorf("want key mismatch for ops request, got %q", invalid["client.ops.csr"])
+	}
+	if len(invalid) != 3 {
+		t.Errorf("want only server and ops files to be invalid, got %v", invalid)
+	}
+}
+
+func TestVerifyDirectoryWithoutClientKey(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := HandleInit(dir, "unsafe.aurae.io", InitOptions{Users: []string{"nova"}}); err != nil {
+		t.Fatalf("could not initialize pki: %s", err)
+	}
+	if err := os.Remove(filepath.Join(dir, "client.nova.key")); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := VerifyDirectory(dir)
+	if err != nil {
+		t.Fatalf("could not verify pki: %s", err)
+	}
+	status := map[string]VerifyResult{}
+	for _, res := range report.Results {
+		status[res.File] = res
+	}
+	if res := status["_signed.client.nova.crt"]; res.Status != VerifyError || !strings.Contains(res.Error, "private key") {
+		t.Fatalf("want missing key to be reported, got %+v", res)
+	}
+	if res := status["client.nova.csr"]; res.Status != VerifyOK {
+		t.Fatalf("want request signature to be verified without key, got %+v", res)
+	}
+}
+
+func TestVerifyClientDirectory(t *testing.T) {
+	pki := t.TempDir()
+	if _, err := HandleInit(pki, "unsafe.aurae.io", InitOptions{Users: []string{"nova"}}); err != nil {
+		t.Fatalf("could not initialize pki: %s", err)
+	}
+	// the layout of config.Default(), without the CA key
+	dir := t.TempDir()
+	for _, file := range []string{"ca.crt", "_signed.client.nova.crt", "client.nova.key"} {
+		b, err := os.ReadFile(filepath.Join(pki, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, file), b, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := VerifyDirectory(dir)
+	if err != nil {
+		t.Fatalf("could not verify pki: %s", err)
+	}
+	if !report.OK() || len(report.Results) != 2 {
+		t.Fatalf("want CA and client certificate to verify, got %+v", report.Results)
+	}
+}
+
+func TestVerifyDirectoryWithoutCA(t *testing.T) {
+	report, err := VerifyDirectory(t.TempDir())
+	if err != nil {
+		t.Fatalf("could not verify pki: %s", err)
+	}
+	if report.OK() || len(report.Results) != 1 || report.Results[0].File != "ca.crt" {
+		t.Fatalf("want missing CA to be reported, got %+v", report.Results)
+	}
+}
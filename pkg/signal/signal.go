@@ -0,0 +1,75 @@
+package signal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Signal numbers as used by auraed on Linux. They are listed here rather
+// than taken from the syscall package, as ae is also built for platforms
+// with different signal numbers.
+var names = map[int32]string{
+	1:  "SIGHUP",
+	2:  "SIGINT",
+	3:  "SIGQUIT",
+	4:  "SIGILL",
+	5:  "SIGTRAP",
+	6:  "SIGABRT",
+	7:  "SIGBUS",
+	8:  "SIGFPE",
+	9:  "SIGKILL",
+	10: "SIGUSR1",
+	11: "SIGSEGV",
+	12: "SIGUSR2",
+	13: "SIGPIPE",
+	14: "SIGALRM",
+	15: "SIGTERM",
+	16: "SIGSTKFLT",
+	17: "SIGCHLD",
+	18: "SIGCONT",
+	19: "SIGSTOP",
+	20: "SIGTSTP",
+	21: "SIGTTIN",
+	22: "SIGTTOU",
+	23: "SIGURG",
+	24: "SIGXCPU",
+	25: "SIGXFSZ",
+	26: "SIGVTALRM",
+	27: "SIGPROF",
+	28: "SIGWINCH",
+	29: "SIGIO",
+	30: "SIGPWR",
+	31: "SIGSYS",
+}
+
+// Name returns the name of a signal number, e.g. "SIGTERM" for 15. Unknown
+// and realtime signals are named by their number.
+func Name(sig int32) string {
+	if name, ok := names[sig]; ok {
+		return name
+	}
+	return fmt.Sprintf("SIG%d", sig)
+}
+
+// Parse returns the signal number of a signal given by number or by name,
+// with or without the "SIG" prefix, e.g. "15", "TERM" or "SIGTERM".
+func Parse(s string) (int32, error) {
+	if n, err := strconv.ParseInt(s, 10, 32); err == nil {
+		if n <= 0 || n > 64 {
+			return 0, fmt.Errorf("invalid signal number %d", n)
+		}
+		return int32(n), nil
+	}
+
+	name := strings.ToUpper(s)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	for sig, n := range names {
+		if n == name {
+			return sig, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown signal %q", s)
+}
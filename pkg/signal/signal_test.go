@@ -0,0 +1,42 @@
+package signal
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	ts := []struct {
+		in      string
+		want    int32
+		wanterr bool
+	}{
+		{"15", 15, false},
+		{"TERM", 15, false},
+		{"SIGKILL", 9, false},
+		{"sigusr1", 10, false},
+		{"34", 34, false},
+		{"0", 0, true},
+		{"65", 0, true},
+		{"FOO", 0, true},
+	}
+
+	for _, tt := range ts {
+		got, err := Parse(tt.in)
+		if tt.wanterr && err == nil {
+			t.Fatalf("[%s] want error, got no error", tt.in)
+		}
+		if !tt.wanterr && err != nil {
+			t.Fatalf("[%s] want no error, got error: %s", tt.in, err)
+		}
+		if got != tt.want {
+			t.Fatalf("[%s] want %d, got %d", tt.in, tt.want, got)
+		}
+	}
+}
+
+func TestName(t *testing.T) {
+	if got := Name(9); got != "SIGKILL" {
+		t.Fatalf("want SIGKILL, got %s", got)
+	}
+	if got := Name(34); got != "SIG34" {
+		t.Fatalf("want SIG34, got %s", got)
+	}
+}
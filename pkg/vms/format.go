@@ -0,0 +1,66 @@
+package vms
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	vmsv0 "github.com/aurae-runtime/ae/pkg/api/v0/vms"
+)
+
+// VM is the summary of a virtual machine printed by ae.
+type VM struct {
+	ID         string   `json:"id"`
+	Status     string   `json:"status"`
+	VCPUs      uint32   `json:"vcpus"`
+	MemoryMB   uint32   `json:"memoryMB"`
+	Kernel     string   `json:"kernel"`
+	KernelArgs []string `json:"kernelArgs,omitempty"`
+	RootDrive  string   `json:"rootDrive"`
+	ReadOnly   bool     `json:"readOnly,omitempty"`
+}
+
+type List []VM
+
+func NewList(machines []*vmsv0.VirtualMachineSummary) List {
+	list := make(List, 0, len(machines))
+	for _, m := range machines {
+		list = append(list, VM{
+			ID:         m.Id,
+			Status:     m.Status,
+			VCPUs:      m.VcpuCount,
+			MemoryMB:   m.MemSizeMb,
+			Kernel:     m.KernelImgPath,
+			KernelArgs: m.KernelArgs,
+			RootDrive:  m.GetRootDrive().GetImagePath(),
+			ReadOnly:   m.GetRootDrive().GetReadOnly(),
+		})
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+func (l List) String() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tVCPUS\tMEMORY\tKERNEL\tROOT DRIVE")
+	for _, vm := range l {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%dMiB\t%s\t%s\n", vm.ID, vm.Status, vm.VCPUs, vm.MemoryMB, vm.Kernel, vm.RootDrive)
+	}
+	w.Flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Result is the outcome of an operation on a single virtual machine.
+type Result struct {
+	ID      string `json:"id"`
+	Address string `json:"address,omitempty"`
+}
+
+func (r Result) String() string {
+	if len(r.Address) != 0 {
+		return fmt.Sprintf("%s\t%s", r.ID, r.Address)
+	}
+	return r.ID
+}
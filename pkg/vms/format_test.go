@@ -0,0 +1,39 @@
+package vms
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	vmsv0 "github.com/aurae-runtime/ae/pkg/api/v0/vms"
+)
+
+func TestListString(t *testing.T) {
+	list := NewList([]*vmsv0.VirtualMachineSummary{
+		{Id: "web", Status: "running", VcpuCount: 2, MemSizeMb: 1024, KernelImgPath: "/vmlinux", RootDrive: &vmsv0.RootDrive{ImagePath: "/web.ext4"}},
+		{Id: "db", Status: "stopped", VcpuCount: 1, MemSizeMb: 512, KernelImgPath: "/vmlinux"},
+	})
+
+	lines := strings.Split(list.String(), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("want header and two vms, got %q", lines)
+	}
+	for i, want := range [][]string{
+		{"ID", "STATUS", "VCPUS", "MEMORY", "KERNEL", "ROOT", "DRIVE"},
+		{"db", "stopped", "1", "512MiB", "/vmlinux"},
+		{"web", "running", "2", "1024MiB", "/vmlinux", "/web.ext4"},
+	} {
+		if got := strings.Fields(lines[i]); !reflect.DeepEqual(got, want) {
+			t.Fatalf("want line %d to be %q, got %q", i, want, got)
+		}
+	}
+}
+
+func TestResultString(t *testing.T) {
+	if got := (Result{ID: "web"}).String(); got != "web" {
+		t.Fatalf("want web, got %q", got)
+	}
+	if got := (Result{ID: "web", Address: "10.0.0.2"}).String(); got != "web\t10.0.0.2" {
+		t.Fatalf("want id and address, got %q", got)
+	}
+}
@@ -0,0 +1,47 @@
+package vms
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	vmsv0 "github.com/aurae-runtime/ae/pkg/api/v0/vms"
+)
+
+type VMs interface {
+	Allocate(context.Context, *vmsv0.VmServiceAllocateRequest) (*vmsv0.VmServiceAllocateResponse, error)
+	Free(context.Context, *vmsv0.VmServiceFreeRequest) (*vmsv0.VmServiceFreeResponse, error)
+	Start(context.Context, *vmsv0.VmServiceStartRequest) (*vmsv0.VmServiceStartResponse, error)
+	Stop(context.Context, *vmsv0.VmServiceStopRequest) (*vmsv0.VmServiceStopResponse, error)
+	List(context.Context, *vmsv0.VmServiceListRequest) (*vmsv0.VmServiceListResponse, error)
+}
+
+type vms struct {
+	client vmsv0.VmServiceClient
+}
+
+func New(ctx context.Context, conn grpc.ClientConnInterface) VMs {
+	return &vms{
+		client: vmsv0.NewVmServiceClient(conn),
+	}
+}
+
+func (v *vms) Allocate(ctx context.Context, req *vmsv0.VmServiceAllocateRequest) (*vmsv0.VmServiceAllocateResponse, error) {
+	return v.client.Allocate(ctx, req)
+}
+
+func (v *vms) Free(ctx context.Context, req *vmsv0.VmServiceFreeRequest) (*vmsv0.VmServiceFreeResponse, error) {
+	return v.client.Free(ctx, req)
+}
+
+func (v *vms) Start(ctx context.Context, req *vmsv0.VmServiceStartRequest) (*vmsv0.VmServiceStartResponse, error) {
+	return v.client.Start(ctx, req)
+}
+
+func (v *vms) Stop(ctx context.Context, req *vmsv0.VmServiceStopRequest) (*vmsv0.VmServiceStopResponse, error) {
+	return v.client.Stop(ctx, req)
+}
+
+func (v *vms) List(ctx context.Context, req *vmsv0.VmServiceListRequest) (*vmsv0.VmServiceListResponse, error) {
+	return v.client.List(ctx, req)
+}